@@ -0,0 +1,28 @@
+package main
+
+import (
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// HumanDuration formats d the way kubectl's AGE column does: seconds below
+// two minutes, minutes below three hours, hours below two days, days below
+// two years, with a second, smaller unit kept only for short spans.
+func HumanDuration(d time.Duration) string {
+	return duration.HumanDuration(d)
+}
+
+// PodAge is the AGE column of pod, computed from its CreationTimestamp.
+func PodAge(pod *apiv1.Pod, now time.Time) string {
+	return translateTimestampSince(pod.CreationTimestamp, now)
+}
+
+func translateTimestampSince(timestamp metav1.Time, now time.Time) string {
+	if timestamp.IsZero() {
+		return "<unknown>"
+	}
+	return HumanDuration(now.Sub(timestamp.Time))
+}
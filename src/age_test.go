@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHumanDuration(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		d      time.Duration
+		expect string
+	}{
+		{-2 * time.Second, "<invalid>"},
+		{0, "0s"},
+		{59 * time.Second, "59s"},
+		{90 * time.Second, "90s"},
+		{119 * time.Second, "119s"},
+		{2 * time.Minute, "2m"},
+		{5*time.Minute + 30*time.Second, "5m30s"},
+		{9*time.Minute + 59*time.Second, "9m59s"},
+		{10 * time.Minute, "10m"},
+		{179 * time.Minute, "179m"},
+		{3 * time.Hour, "3h"},
+		{3*time.Hour + 20*time.Minute, "3h20m"},
+		{7*time.Hour + 59*time.Minute, "7h59m"},
+		{8*time.Hour + 30*time.Minute, "8h"},
+		{47 * time.Hour, "47h"},
+		{2 * day, "2d"},
+		{2*day + 5*time.Hour, "2d5h"},
+		{7*day + 23*time.Hour, "7d23h"},
+		{8*day + 23*time.Hour, "8d"},
+		{36 * day, "36d"},
+		{729 * day, "729d"},
+		{730 * day, "2y"},
+		{740 * day, "2y10d"},
+		{8 * 365 * day, "8y"},
+	}
+
+	for i, test := range tests {
+		formatted := HumanDuration(test.d)
+		if !reflect.DeepEqual(test.expect, formatted) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, formatted))
+		}
+	}
+}
+
+func TestPodAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect string
+	}{
+		{apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test1", CreationTimestamp: metav1.NewTime(now.Add(-36 * 24 * time.Hour))}}, "36d"},
+		{apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test2", CreationTimestamp: metav1.NewTime(now.Add(-90 * time.Second))}}, "90s"},
+		{apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test3"}}, "<unknown>"},
+	}
+
+	for i, test := range tests {
+		age := PodAge(&test.pod, now)
+		if !reflect.DeepEqual(test.expect, age) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, age))
+		}
+	}
+}
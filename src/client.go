@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// FetchPods lists the pods of namespace, or of all namespaces when namespace
+// is empty. A non-empty labelSelector, e.g. "app=nginx", is validated and
+// replaces opts.LabelSelector.
+func FetchPods(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string, opts metav1.ListOptions) (*apiv1.PodList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if labelSelector != "" {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", labelSelector, err)
+		}
+		opts.LabelSelector = selector.String()
+	}
+	return clientset.CoreV1().Pods(namespace).List(ctx, opts)
+}
+
+// WatchPods prints a row for every pod of namespace (all namespaces when
+// empty) matching labelSelector (all pods when empty) as it is added or
+// updated, and a DELETED line when it goes away, like "kubectl get pods -w".
+// It blocks until ctx is cancelled.
+func WatchPods(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string, w io.Writer) error {
+	rows := &watchRowWriter{w: w}
+	return runPodInformer(ctx, clientset, namespace, labelSelector, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*apiv1.Pod); ok {
+				rows.write(BuildPodRow(pod))
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pod, ok := obj.(*apiv1.Pod); ok {
+				rows.write(BuildPodRow(pod))
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if pod := deletedPod(obj); pod != nil {
+				fmt.Fprintf(w, "DELETED %s\n", pod.Name)
+			}
+		},
+	})
+}
+
+// WatchPodEvents prints an audit log line per event of the pods of namespace
+// (all namespaces when empty) matching labelSelector, e.g.
+// "2024-01-01T00:00:00Z MODIFIED web-abc Running->CrashLoopBackOff", instead
+// of table rows. An update shows old->new when the status changed. It blocks
+// until ctx is cancelled.
+func WatchPodEvents(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string, w io.Writer) error {
+	return watchPodEvents(ctx, clientset, namespace, labelSelector, w, time.Now)
+}
+
+func watchPodEvents(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string, w io.Writer, now func() time.Time) error {
+	logEvent := func(event string, pod *apiv1.Pod, status string) {
+		fmt.Fprintf(w, "%s %s %s %s\n", now().UTC().Format(time.RFC3339), event, pod.Name, status)
+	}
+	return runPodInformer(ctx, clientset, namespace, labelSelector, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*apiv1.Pod); ok {
+				logEvent("ADDED", pod, printReason(pod))
+			}
+		},
+		UpdateFunc: func(oldObj, obj interface{}) {
+			oldPod, _ := oldObj.(*apiv1.Pod)
+			pod, ok := obj.(*apiv1.Pod)
+			if !ok {
+				return
+			}
+			if changed, from, to := SummaryChanged(oldPod, pod, now()); changed && oldPod != nil {
+				logEvent("MODIFIED", pod, from+"->"+to)
+			} else {
+				logEvent("MODIFIED", pod, to)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if pod := deletedPod(obj); pod != nil {
+				logEvent("DELETED", pod, printReason(pod))
+			}
+		},
+	})
+}
+
+// runPodInformer runs handler over the pods of namespace matching
+// labelSelector until ctx is cancelled. Handlers of a single registration are
+// called sequentially, so their writes never interleave.
+func runPodInformer(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string, handler cache.ResourceEventHandler) error {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return fmt.Errorf("invalid label selector %q: %w", labelSelector, err)
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector.String()
+		}),
+	)
+	informer := factory.Core().V1().Pods().Informer()
+	if _, err := informer.AddEventHandler(handler); err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+	return nil
+}
+
+// deletedPod returns the pod of a delete notification, unwrapping the
+// tombstone left when the final state is unknown.
+func deletedPod(obj interface{}) *apiv1.Pod {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, _ := obj.(*apiv1.Pod)
+	return pod
+}
+
+// watchRowWriter writes watch rows with columns padded to the widest value
+// seen so far, so rows written one event at a time still line up. A column
+// only grows, so rows written before a wider value stay narrower.
+type watchRowWriter struct {
+	w      io.Writer
+	widths [4]int
+}
+
+func (r *watchRowWriter) write(row PodRow) {
+	columns := []string{row.Name, row.Ready, row.Status, row.Restarts}
+	var line strings.Builder
+	for i, column := range columns {
+		if n := utf8.RuneCountInString(column); n > r.widths[i] {
+			r.widths[i] = n
+		}
+		fmt.Fprintf(&line, "%s%s", column, strings.Repeat(" ", r.widths[i]-utf8.RuneCountInString(column)+tabwriterPadding))
+	}
+	line.WriteString(HumanDuration(row.Age))
+	fmt.Fprintln(r.w, line.String())
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestFetchPods(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: apiv1.PodRunning},
+		},
+		&apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+		},
+	)
+
+	tests := []struct {
+		namespace string
+		selector  string
+		expect    []string
+	}{
+		{"default", "", []string{"web"}},
+		{"", "", []string{"coredns", "web"}},
+		{"empty", "", nil},
+		{"", "app=web", []string{"web"}},
+		{"", "app=nginx", nil},
+	}
+
+	for i, test := range tests {
+		pods, err := FetchPods(context.Background(), clientset, test.namespace, test.selector, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("%d unexpected error: %v", i, err)
+		}
+		var names []string
+		for _, pod := range pods.Items {
+			names = append(names, pod.Name)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(test.expect, names) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, names))
+		}
+	}
+
+	pods, err := FetchPods(context.Background(), clientset, "default", "", metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := PrintPodTable(&buf, pods, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "NAME") || !strings.HasPrefix(lines[1], "web ") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+	buf.Reset()
+	if err := PrintPodTable(&buf, pods, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "\n\n1 Running\n") {
+		t.Errorf("missing summary:\n%s", buf.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := FetchPods(ctx, clientset, "default", "", metav1.ListOptions{}); err == nil {
+		t.Errorf("expected an error for a cancelled context")
+	}
+	if _, err := FetchPods(context.Background(), clientset, "default", "app in (web", metav1.ListOptions{}); err == nil {
+		t.Errorf("expected an error for a malformed selector")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the informer goroutines to write to
+// while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func waitForOutput(t *testing.T, out *syncBuffer, substr string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), substr) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %q, got:\n%s", substr, out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchPods(t *testing.T) {
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "1", Labels: map[string]string{"app": "web"}},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+	}
+	other := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", ResourceVersion: "1", Labels: map[string]string{"app": "api"}},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+	}
+	clientset := fake.NewSimpleClientset(pod, other)
+	watcher := watch.NewFakeWithChanSize(10, false)
+	clientset.PrependWatchReactor("pods", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error)
+	go func() {
+		done <- WatchPods(ctx, clientset, "default", "app=web", out)
+	}()
+
+	waitForOutput(t, out, "web   0/1   Pending   0")
+
+	crashing := pod.DeepCopy()
+	crashing.ResourceVersion = "2"
+	crashing.Status.Phase = apiv1.PodRunning
+	crashing.Status.ContainerStatuses = []apiv1.ContainerStatus{
+		{RestartCount: 1, State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+	}
+	watcher.Modify(crashing)
+	waitForOutput(t, out, "web   0/1   CrashLoopBackOff   1")
+
+	pending := crashing.DeepCopy()
+	pending.ResourceVersion = "3"
+	pending.Status = apiv1.PodStatus{Phase: apiv1.PodPending}
+	watcher.Modify(pending)
+	// the status column keeps the width of CrashLoopBackOff
+	waitForOutput(t, out, "web   0/1   Pending            0")
+
+	watcher.Delete(pending)
+	waitForOutput(t, out, "DELETED web\n")
+	if strings.Contains(out.String(), "api") {
+		t.Errorf("expected pods not matching the selector to be left out, got:\n%s", out.String())
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchPods did not stop after the context was cancelled")
+	}
+
+	if err := WatchPods(context.Background(), clientset, "default", "app in (web", out); err == nil {
+		t.Errorf("expected an error for a malformed selector")
+	}
+}
+
+func TestWatchPodEvents(t *testing.T) {
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "1"},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+	}
+	clientset := fake.NewSimpleClientset(pod)
+	watcher := watch.NewFakeWithChanSize(10, false)
+	clientset.PrependWatchReactor("pods", k8stesting.DefaultWatchReactor(watcher, nil))
+	now := func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error)
+	go func() {
+		done <- watchPodEvents(ctx, clientset, "default", "", out, now)
+	}()
+
+	waitForOutput(t, out, "2024-01-01T00:00:00Z ADDED web Pending\n")
+
+	running := pod.DeepCopy()
+	running.ResourceVersion = "2"
+	running.Status.Phase = apiv1.PodRunning
+	running.Status.ContainerStatuses = []apiv1.ContainerStatus{
+		{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+	}
+	watcher.Modify(running)
+	waitForOutput(t, out, "2024-01-01T00:00:00Z MODIFIED web Pending->Running\n")
+
+	relabeled := running.DeepCopy()
+	relabeled.ResourceVersion = "3"
+	relabeled.Labels = map[string]string{"canary": "true"}
+	watcher.Modify(relabeled)
+	waitForOutput(t, out, "2024-01-01T00:00:00Z MODIFIED web Running\n")
+
+	watcher.Delete(relabeled)
+	waitForOutput(t, out, "2024-01-01T00:00:00Z DELETED web Running\n")
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchPodEvents did not stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+var statusColors = map[string]string{
+	"CrashLoopBackOff":  ansiRed,
+	"Error":             ansiRed,
+	"OOMKilled":         ansiRed,
+	"Pending":           ansiYellow,
+	"ContainerCreating": ansiYellow,
+	"Running":           ansiGreen,
+	"Completed":         ansiGreen,
+}
+
+var severityColors = map[Severity]string{
+	Healthy: ansiGreen,
+	Pending: ansiYellow,
+	Error:   ansiRed,
+}
+
+// ColorizeStatus wraps status in an ANSI color matching its health: red for
+// crashes, yellow for pending and init states, green for Running and
+// Completed. Other statuses, and every status when enable is false, are
+// returned unchanged so callers can turn color off when not writing to a TTY.
+func ColorizeStatus(status string, enable bool) string {
+	if !enable {
+		return status
+	}
+	color, ok := statusColors[status]
+	if !ok && strings.HasPrefix(status, "Init:") {
+		color, ok = ansiYellow, true
+	}
+	if !ok {
+		return status
+	}
+	return color + status + ansiReset
+}
+
+// ColorizeReason wraps reason in the ANSI color of its ClassifyReason
+// severity, so unlike ColorizeStatus it also colors e.g. ImagePullBackOff and
+// Init:CrashLoopBackOff red.
+func ColorizeReason(reason string) string {
+	color, ok := severityColors[ClassifyReason(reason)]
+	if !ok {
+		return reason
+	}
+	return color + reason + ansiReset
+}
+
+var (
+	severityGlyphs = map[Severity]rune{
+		Healthy: '+',
+		Pending: '.',
+		Warning: '!',
+		Error:   'x',
+		Unknown: '?',
+	}
+	severityGlyphsUnicode = map[Severity]rune{
+		Healthy: '✓',
+		Pending: '◌',
+		Warning: '⚠',
+		Error:   '✗',
+		Unknown: '?',
+	}
+)
+
+// StatusGlyph returns a one-character ASCII health indicator for pod, for dense
+// grid views, from the ClassifyReason severity of its reason: '+' healthy,
+// '.' pending, '!' warning, 'x' error and '?' unknown.
+func StatusGlyph(pod *apiv1.Pod) rune {
+	return severityGlyphs[ClassifyReason(printReason(pod))]
+}
+
+// StatusGlyphUnicode is StatusGlyph with prettier symbols for terminals that
+// render them: '✓', '◌', '⚠', '✗' and '?'.
+func StatusGlyphUnicode(pod *apiv1.Pod) rune {
+	return severityGlyphsUnicode[ClassifyReason(printReason(pod))]
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestColorizeStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		enable bool
+		expect string
+	}{
+		{"CrashLoopBackOff", true, "\x1b[31mCrashLoopBackOff\x1b[0m"},
+		{"Error", true, "\x1b[31mError\x1b[0m"},
+		{"OOMKilled", true, "\x1b[31mOOMKilled\x1b[0m"},
+		{"Pending", true, "\x1b[33mPending\x1b[0m"},
+		{"Init:1/2", true, "\x1b[33mInit:1/2\x1b[0m"},
+		{"ContainerCreating", true, "\x1b[33mContainerCreating\x1b[0m"},
+		{"Running", true, "\x1b[32mRunning\x1b[0m"},
+		{"Completed", true, "\x1b[32mCompleted\x1b[0m"},
+		{"Terminating", true, "Terminating"},
+		{"Init:CrashLoopBackOff", true, "\x1b[33mInit:CrashLoopBackOff\x1b[0m"},
+		{"ImagePullBackOff", true, "ImagePullBackOff"},
+		{"CrashLoopBackOff", false, "CrashLoopBackOff"},
+		{"Running", false, "Running"},
+	}
+
+	for i, test := range tests {
+		colored := ColorizeStatus(test.status, test.enable)
+		if !reflect.DeepEqual(test.expect, colored) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, colored))
+		}
+	}
+}
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestColorizeReason(t *testing.T) {
+	tests := []struct {
+		reason string
+		expect string
+	}{
+		{"Running", ansiGreen},
+		{"Init:0/1", ansiYellow},
+		{"Init:CrashLoopBackOff", ansiRed},
+		{"Evicted", ansiRed},
+		{"NotReady", ""},
+	}
+
+	for i, test := range tests {
+		colored := ColorizeReason(test.reason)
+		if stripped := ansiPattern.ReplaceAllString(colored, ""); stripped != test.reason {
+			t.Errorf("%d stripping codes did not restore the reason: %s", i, cmp.Diff(test.reason, stripped))
+		}
+		expect := test.reason
+		if test.expect != "" {
+			expect = test.expect + test.reason + ansiReset
+		}
+		if !reflect.DeepEqual(expect, colored) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(expect, colored))
+		}
+	}
+}
+
+func TestStatusGlyph(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(phase apiv1.PodPhase, age time.Duration, state apiv1.ContainerState) apiv1.Pod {
+		pod := containerPod(phase, state)
+		pod.CreationTimestamp = metav1.NewTime(now.Add(-age))
+		return pod
+	}
+	waiting := func(reason string) apiv1.ContainerState {
+		return apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: reason}}
+	}
+
+	terminating := pod(apiv1.PodRunning, time.Minute, runningState)
+	deleted := metav1.NewTime(now)
+	terminating.DeletionTimestamp = &deleted
+
+	tests := []struct {
+		pod           apiv1.Pod
+		expect        rune
+		expectUnicode rune
+	}{
+		// Test Healthy
+		{pod(apiv1.PodRunning, time.Minute, runningState), '+', '✓'},
+		// Test Pending
+		{pod(apiv1.PodPending, time.Minute, waiting("ContainerCreating")), '.', '◌'},
+		// Test Pending, however long the pod has been waiting
+		{pod(apiv1.PodPending, time.Hour, waiting("ContainerCreating")), '.', '◌'},
+		// Test Warning
+		{terminating, '!', '⚠'},
+		// Test Error
+		{pod(apiv1.PodRunning, time.Minute, crashLoopState), 'x', '✗'},
+		// Test Unknown
+		{pod(apiv1.PodRunning, time.Minute, waiting("SomethingNew")), '?', '?'},
+	}
+
+	for i, test := range tests {
+		if glyph := StatusGlyph(&test.pod); glyph != test.expect {
+			t.Errorf("%d mismatch: got %q, expected %q", i, glyph, test.expect)
+		}
+		if glyph := StatusGlyphUnicode(&test.pod); glyph != test.expectUnicode {
+			t.Errorf("%d mismatch: got %q, expected %q", i, glyph, test.expectUnicode)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+var missingConfigPattern = regexp.MustCompile(`(?i)\b(configmap|secret)s? "?([a-z0-9.-]+)"? not found`)
+
+var webhookFailurePattern = regexp.MustCompile(`admission webhook "[^"]+" denied the request|failed calling webhook "[^"]+"`)
+
+// eventsForPod returns the events whose involved object is pod.
+func eventsForPod(pod *apiv1.Pod, events []apiv1.Event) []apiv1.Event {
+	var matched []apiv1.Event
+	for _, event := range events {
+		involved := event.InvolvedObject
+		if involved.Kind != "" && involved.Kind != "Pod" {
+			continue
+		}
+		if involved.UID != "" && pod.UID != "" {
+			if involved.UID == pod.UID {
+				matched = append(matched, event)
+			}
+			continue
+		}
+		if involved.Name == pod.Name && involved.Namespace == pod.Namespace {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// MissingConfigResource reports whether pod is failing because a ConfigMap or
+// Secret it references does not exist, returning it as "configmap/<name>" or
+// "secret/<name>".
+func MissingConfigResource(pod *apiv1.Pod, events []apiv1.Event) (bool, string) {
+	for _, event := range eventsForPod(pod, events) {
+		if event.Reason != "FailedMount" && event.Reason != "Failed" {
+			continue
+		}
+		if match := missingConfigPattern.FindStringSubmatch(event.Message); match != nil {
+			return true, strings.ToLower(match[1]) + "/" + match[2]
+		}
+	}
+	return false, ""
+}
+
+// BlockedByWebhook reports whether an admission webhook rejected or failed a
+// request for pod, returning the event message, which names the webhook. For
+// pods that were never created the failure is recorded on the owner, so events
+// of the pod's controller are scanned as well.
+func BlockedByWebhook(pod *apiv1.Pod, events []apiv1.Event) (bool, string) {
+	for _, event := range events {
+		if !eventInvolvesPodOrOwner(pod, event) {
+			continue
+		}
+		if webhookFailurePattern.MatchString(event.Message) {
+			return true, event.Message
+		}
+	}
+	return false, ""
+}
+
+func eventInvolvesPodOrOwner(pod *apiv1.Pod, event apiv1.Event) bool {
+	if len(eventsForPod(pod, []apiv1.Event{event})) > 0 {
+		return true
+	}
+	involved := event.InvolvedObject
+	for _, owner := range pod.OwnerReferences {
+		if involved.Kind == owner.Kind && involved.Name == owner.Name && involved.Namespace == pod.Namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podEvent(pod *apiv1.Pod, reason, message string) apiv1.Event {
+	return apiv1.Event{
+		InvolvedObject: apiv1.ObjectReference{Kind: "Pod", Name: pod.Name, Namespace: pod.Namespace, UID: pod.UID},
+		Reason:         reason,
+		Message:        message,
+		Type:           apiv1.EventTypeWarning,
+	}
+}
+
+func TestMissingConfigResource(t *testing.T) {
+	pod := apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", UID: "uid-1"}}
+	other := apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod", UID: "uid-2"}}
+
+	tests := []struct {
+		events        []apiv1.Event
+		expectMissing bool
+		expectName    string
+	}{
+		{
+			[]apiv1.Event{podEvent(&pod, "FailedMount", `MountVolume.SetUp failed for volume "config" : configmap "app-config" not found`)},
+			true,
+			"configmap/app-config",
+		},
+		{
+			[]apiv1.Event{
+				podEvent(&pod, "Scheduled", "Successfully assigned prod/web to node-1"),
+				podEvent(&pod, "Failed", `Error: secret "db-credentials" not found`),
+			},
+			true,
+			"secret/db-credentials",
+		},
+		{
+			[]apiv1.Event{podEvent(&other, "FailedMount", `MountVolume.SetUp failed for volume "config" : configmap "app-config" not found`)},
+			false,
+			"",
+		},
+		{
+			[]apiv1.Event{podEvent(&pod, "Failed", "Error: ErrImagePull")},
+			false,
+			"",
+		},
+	}
+
+	for i, test := range tests {
+		missing, name := MissingConfigResource(&pod, test.events)
+		if missing != test.expectMissing || name != test.expectName {
+			t.Errorf("%d mismatch: got (%v, %q), expected (%v, %q)", i, missing, name, test.expectMissing, test.expectName)
+		}
+	}
+}
+
+func TestBlockedByWebhook(t *testing.T) {
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-7d4b9c8f5-x2k4p",
+			Namespace:       "prod",
+			UID:             "uid-1",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-7d4b9c8f5"}},
+		},
+	}
+	denied := `admission webhook "policy.example.com" denied the request: containers must not run as root`
+	unreachable := `Internal error occurred: failed calling webhook "inject.example.com": context deadline exceeded`
+
+	tests := []struct {
+		events        []apiv1.Event
+		expectBlocked bool
+		expectMessage string
+	}{
+		{
+			[]apiv1.Event{podEvent(&pod, "FailedCreate", denied)},
+			true,
+			denied,
+		},
+		{
+			[]apiv1.Event{{
+				InvolvedObject: apiv1.ObjectReference{Kind: "ReplicaSet", Name: "web-7d4b9c8f5", Namespace: "prod"},
+				Reason:         "FailedCreate",
+				Message:        "Error creating: " + unreachable,
+			}},
+			true,
+			"Error creating: " + unreachable,
+		},
+		{
+			[]apiv1.Event{{
+				InvolvedObject: apiv1.ObjectReference{Kind: "ReplicaSet", Name: "api-5f6d", Namespace: "prod"},
+				Reason:         "FailedCreate",
+				Message:        "Error creating: " + denied,
+			}},
+			false,
+			"",
+		},
+		{
+			[]apiv1.Event{podEvent(&pod, "Scheduled", "Successfully assigned prod/web-7d4b9c8f5-x2k4p to node-1")},
+			false,
+			"",
+		},
+	}
+
+	for i, test := range tests {
+		blocked, message := BlockedByWebhook(&pod, test.events)
+		if blocked != test.expectBlocked || message != test.expectMessage {
+			t.Errorf("%d mismatch: got (%v, %q), expected (%v, %q)", i, blocked, message, test.expectBlocked, test.expectMessage)
+		}
+	}
+}
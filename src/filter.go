@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// FilterPodsByLabels keeps the pods matching selector, e.g. "app=nginx", so
+// pods loaded from a file can be filtered like a listing with -l.
+func FilterPodsByLabels(pods *apiv1.PodList, selector string) (*apiv1.PodList, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", selector, err)
+	}
+
+	filtered := &apiv1.PodList{TypeMeta: pods.TypeMeta, ListMeta: pods.ListMeta}
+	for _, pod := range pods.Items {
+		if parsed.Matches(labels.Set(pod.Labels)) {
+			filtered.Items = append(filtered.Items, pod)
+		}
+	}
+	return filtered, nil
+}
+
+// FilterPodsByPhase keeps the pods whose status phase is one of phases.
+func FilterPodsByPhase(pods *apiv1.PodList, phases ...apiv1.PodPhase) *apiv1.PodList {
+	filtered := &apiv1.PodList{TypeMeta: pods.TypeMeta, ListMeta: pods.ListMeta}
+	for _, pod := range pods.Items {
+		for _, phase := range phases {
+			if pod.Status.Phase == phase {
+				filtered.Items = append(filtered.Items, pod)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+var healthyReasons = map[string]bool{
+	"Running":   true,
+	"Completed": true,
+	"Succeeded": true,
+}
+
+// FilterUnhealthy keeps the pods whose computed reason is not healthy. It goes
+// by the reason rather than the phase, so a CrashLoopBackOff pod still in the
+// Running phase is kept.
+func FilterUnhealthy(pods *apiv1.PodList) *apiv1.PodList {
+	filtered := &apiv1.PodList{TypeMeta: pods.TypeMeta, ListMeta: pods.ListMeta}
+	for i := range pods.Items {
+		if !healthyReasons[computePodStatus(&pods.Items[i]).reason] {
+			filtered.Items = append(filtered.Items, pods.Items[i])
+		}
+	}
+	return filtered
+}
+
+// FilterPods returns the pods whose Summarize result satisfies pred, e.g.
+// ClassifyReason(s.Reason) == Error. The result is a new slice, so pods may be
+// an informer's cache.
+func FilterPods(pods []apiv1.Pod, now time.Time, pred func(PodSummary) bool) []apiv1.Pod {
+	var filtered []apiv1.Pod
+	for i := range pods {
+		if pred(Summarize(&pods[i], now)) {
+			filtered = append(filtered, pods[i])
+		}
+	}
+	return filtered
+}
+
+// OnCordonedNode returns the pods scheduled to one of the cordoned nodes, i.e.
+// the pods a drain of those nodes would evict.
+func OnCordonedNode(pods []apiv1.Pod, cordoned map[string]bool) []apiv1.Pod {
+	var matched []apiv1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" && cordoned[pod.Spec.NodeName] {
+			matched = append(matched, pod)
+		}
+	}
+	return matched
+}
+
+// EvictableByTaint returns the pods that would be evicted if taint were added
+// to their node, i.e. the pods not tolerating it when it is NoExecute. Pods
+// tolerating it for a limited TolerationSeconds still count as tolerating.
+func EvictableByTaint(pods []apiv1.Pod, taint apiv1.Taint) []apiv1.Pod {
+	if taint.Effect != apiv1.TaintEffectNoExecute {
+		return nil
+	}
+	var evictable []apiv1.Pod
+	for _, pod := range pods {
+		if !toleratesTaint(pod.Spec.Tolerations, &taint) {
+			evictable = append(evictable, pod)
+		}
+	}
+	return evictable
+}
+
+func toleratesTaint(tolerations []apiv1.Toleration, taint *apiv1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
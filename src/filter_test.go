@@ -0,0 +1,218 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podNames(pods []apiv1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestFilterPodsByLabels(t *testing.T) {
+	pods := &apiv1.PodList{
+		Items: []apiv1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-1", Labels: map[string]string{"app": "nginx", "tier": "web"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "redis-1", Labels: map[string]string{"app": "redis"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nginx-2", Labels: map[string]string{"app": "nginx"}}},
+		},
+	}
+
+	tests := []struct {
+		selector  string
+		expect    []string
+		expectErr bool
+	}{
+		{"app=nginx", []string{"nginx-1", "nginx-2"}, false},
+		{"app=nginx,tier=web", []string{"nginx-1"}, false},
+		{"app=postgres", nil, false},
+		{"app in (nginx", nil, true},
+	}
+
+	for i, test := range tests {
+		filtered, err := FilterPodsByLabels(pods, test.selector)
+		if (err != nil) != test.expectErr {
+			t.Fatalf("%d unexpected error: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+		if names := podNames(filtered.Items); !reflect.DeepEqual(test.expect, names) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, names))
+		}
+	}
+}
+
+func TestFilterPodsByPhase(t *testing.T) {
+	pods := &apiv1.PodList{
+		Items: []apiv1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "running"}, Status: apiv1.PodStatus{Phase: apiv1.PodRunning}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "pending"}, Status: apiv1.PodStatus{Phase: apiv1.PodPending}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "failed"}, Status: apiv1.PodStatus{Phase: apiv1.PodFailed}},
+		},
+	}
+
+	tests := []struct {
+		phases []apiv1.PodPhase
+		expect []string
+	}{
+		{[]apiv1.PodPhase{apiv1.PodRunning}, []string{"running"}},
+		{[]apiv1.PodPhase{apiv1.PodPending, apiv1.PodFailed}, []string{"pending", "failed"}},
+		{[]apiv1.PodPhase{apiv1.PodSucceeded}, nil},
+		{nil, nil},
+	}
+
+	for i, test := range tests {
+		names := podNames(FilterPodsByPhase(pods, test.phases...).Items)
+		if !reflect.DeepEqual(test.expect, names) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, names))
+		}
+	}
+}
+
+func TestFilterUnhealthy(t *testing.T) {
+	pods := &apiv1.PodList{
+		Items: []apiv1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "healthy"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:             apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "crashing"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:             apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "done"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodSucceeded},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+			},
+		},
+	}
+
+	expect := []string{"crashing", "pending"}
+	names := podNames(FilterUnhealthy(pods).Items)
+	if !reflect.DeepEqual(expect, names) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, names))
+	}
+}
+
+func TestFilterPods(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(name, waitingReason string) apiv1.Pod {
+		status := apiv1.ContainerStatus{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}
+		if waitingReason != "" {
+			status = apiv1.ContainerStatus{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: waitingReason}}}
+		}
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: []apiv1.ContainerStatus{status}},
+		}
+	}
+	pods := []apiv1.Pod{
+		pod("web", ""),
+		pod("api", "CrashLoopBackOff"),
+		pod("worker", "ImagePullBackOff"),
+		pod("batch", "CrashLoopBackOff"),
+	}
+
+	tests := []struct {
+		pred   func(PodSummary) bool
+		expect []string
+	}{
+		{func(s PodSummary) bool { return s.Reason == "CrashLoopBackOff" }, []string{"api", "batch"}},
+		{func(s PodSummary) bool { return ClassifyReason(s.Reason) == Error }, []string{"api", "worker", "batch"}},
+		{func(s PodSummary) bool { return s.Reason == "Completed" }, nil},
+	}
+
+	for i, test := range tests {
+		filtered := FilterPods(pods, now, test.pred)
+		if names := podNames(filtered); !reflect.DeepEqual(test.expect, names) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, names))
+		}
+		for j := range filtered {
+			filtered[j].Name = "changed"
+		}
+	}
+	if names := podNames(pods); !reflect.DeepEqual([]string{"web", "api", "worker", "batch"}, names) {
+		t.Errorf("FilterPods modified its input: %v", names)
+	}
+}
+
+func TestOnCordonedNode(t *testing.T) {
+	pod := func(name, node string) apiv1.Pod {
+		return apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: apiv1.PodSpec{NodeName: node}}
+	}
+	pods := []apiv1.Pod{
+		pod("web-1", "node-1"),
+		pod("web-2", "node-2"),
+		pod("db-0", "node-1"),
+		pod("queued", ""),
+	}
+
+	tests := []struct {
+		cordoned map[string]bool
+		expect   []string
+	}{
+		{map[string]bool{"node-1": true}, []string{"web-1", "db-0"}},
+		{map[string]bool{"node-1": false, "node-3": true}, nil},
+		{nil, nil},
+	}
+
+	for i, test := range tests {
+		names := podNames(OnCordonedNode(pods, test.cordoned))
+		if !reflect.DeepEqual(test.expect, names) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, names))
+		}
+	}
+}
+
+func TestEvictableByTaint(t *testing.T) {
+	pod := func(name string, tolerations ...apiv1.Toleration) apiv1.Pod {
+		return apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: apiv1.PodSpec{NodeName: "node-1", Tolerations: tolerations}}
+	}
+	pods := []apiv1.Pod{
+		pod("web"),
+		pod("agent", apiv1.Toleration{Operator: apiv1.TolerationOpExists}),
+		pod("db", apiv1.Toleration{Key: "maintenance", Operator: apiv1.TolerationOpEqual, Value: "planned", Effect: apiv1.TaintEffectNoExecute}),
+		pod("cache", apiv1.Toleration{Key: "maintenance", Operator: apiv1.TolerationOpEqual, Value: "other", Effect: apiv1.TaintEffectNoExecute}),
+		pod("batch", apiv1.Toleration{Key: "maintenance", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoSchedule}),
+	}
+
+	tests := []struct {
+		taint  apiv1.Taint
+		expect []string
+	}{
+		{apiv1.Taint{Key: "maintenance", Value: "planned", Effect: apiv1.TaintEffectNoExecute}, []string{"web", "cache", "batch"}},
+		{apiv1.Taint{Key: "maintenance", Value: "planned", Effect: apiv1.TaintEffectNoSchedule}, nil},
+	}
+
+	for i, test := range tests {
+		names := podNames(EvictableByTaint(pods, test.taint))
+		if !reflect.DeepEqual(test.expect, names) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, names))
+		}
+	}
+}
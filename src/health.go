@@ -0,0 +1,801 @@
+package main
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CrashLoopOptions tunes when a container that is not currently backing off
+// is still considered crash-looping.
+type CrashLoopOptions struct {
+	// RestartThreshold is the restart count at or above which a recent
+	// termination marks the container as crash-looping.
+	RestartThreshold int32
+	// Window is how recent the last termination has to be.
+	Window time.Duration
+}
+
+var DefaultCrashLoopOptions = CrashLoopOptions{
+	RestartThreshold: 5,
+	Window:           10 * time.Minute,
+}
+
+// IsCrashLooping reports whether any regular or init container of pod is
+// crash-looping, using DefaultCrashLoopOptions.
+func IsCrashLooping(pod *apiv1.Pod) bool {
+	return DefaultCrashLoopOptions.IsCrashLooping(pod, time.Now())
+}
+
+// IsCrashLooping reports whether any regular or init container of pod is in
+// CrashLoopBackOff, or has restarted at least RestartThreshold times and last
+// terminated within Window of now.
+func (o CrashLoopOptions) IsCrashLooping(pod *apiv1.Pod, now time.Time) bool {
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, container := range statuses {
+			if container.State.Waiting != nil && container.State.Waiting.Reason == "CrashLoopBackOff" {
+				return true
+			}
+			if container.RestartCount < o.RestartThreshold || o.RestartThreshold <= 0 {
+				continue
+			}
+			if terminated := lastTermination(container); terminated != nil && now.Sub(terminated.FinishedAt.Time) <= o.Window {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ContainerReadyAges returns, per running container, how long ago it started.
+// Containers that are not running are skipped.
+func ContainerReadyAges(pod *apiv1.Pod, now time.Time) map[string]time.Duration {
+	ages := make(map[string]time.Duration)
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.State.Running == nil {
+			continue
+		}
+		ages[container.Name] = now.Sub(container.State.Running.StartedAt.Time)
+	}
+	return ages
+}
+
+// AvailableReplicas counts the pods that are Ready and not terminating, which
+// reflects serving capacity better than raw ready counts during a rollout.
+func AvailableReplicas(pods []apiv1.Pod) int {
+	available := 0
+	for i := range pods {
+		if pods[i].DeletionTimestamp == nil && hasPodReadyCondition(pods[i].Status.Conditions) {
+			available++
+		}
+	}
+	return available
+}
+
+// ReplicasMet reports whether at least expected pods are Running and Ready,
+// a simple readiness gate that needs no controller. Terminating pods do not
+// count.
+func ReplicasMet(pods []apiv1.Pod, expected int) bool {
+	met := 0
+	for i := range pods {
+		if printReason(&pods[i]) == string(apiv1.PodRunning) && hasPodReadyCondition(pods[i].Status.Conditions) {
+			met++
+		}
+	}
+	return met >= expected
+}
+
+var (
+	uidPattern      = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	hexTokenPattern = regexp.MustCompile(`\b[0-9a-f]{10,}\b`)
+)
+
+// FailureSignatures groups failing pods by a signature made of their reason
+// and primary message, mapping each signature to the names of the pods that
+// share it. Pod names, UIDs and long hex identifiers are stripped from the
+// message so pods failing the same way collapse into one signature.
+func FailureSignatures(pods []apiv1.Pod) map[string][]string {
+	signatures := make(map[string][]string)
+	for i := range pods {
+		pod := &pods[i]
+		reason := printReason(pod)
+		switch reason {
+		case "Running", "Completed", string(apiv1.PodSucceeded):
+			continue
+		}
+
+		signature := reason
+		if message := normalizeFailureMessage(pod, primaryMessage(pod)); message != "" {
+			signature += ": " + message
+		}
+		signatures[signature] = append(signatures[signature], pod.Name)
+	}
+	return signatures
+}
+
+// primaryMessage returns the message of the first container not running, or
+// the pod-level message when no container carries one.
+func primaryMessage(pod *apiv1.Pod) string {
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, container := range statuses {
+			if container.State.Waiting != nil && container.State.Waiting.Message != "" {
+				return container.State.Waiting.Message
+			}
+			if container.State.Terminated != nil && container.State.Terminated.Message != "" {
+				return container.State.Terminated.Message
+			}
+		}
+	}
+	return pod.Status.Message
+}
+
+func normalizeFailureMessage(pod *apiv1.Pod, message string) string {
+	if pod.Name != "" {
+		message = strings.ReplaceAll(message, pod.Name, "<pod>")
+	}
+	message = uidPattern.ReplaceAllString(message, "<uid>")
+	message = hexTokenPattern.ReplaceAllString(message, "<id>")
+	return strings.TrimSpace(message)
+}
+
+// GateRemovalReady reports whether every scheduling gate of pod is satisfied
+// according to the caller's predicate, i.e. whether all gates can be removed.
+func GateRemovalReady(pod *apiv1.Pod, satisfied func(gate string) bool) bool {
+	for _, gate := range pod.Spec.SchedulingGates {
+		if !satisfied(gate.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimateInitRemaining linearly extrapolates how long the remaining init
+// containers of an "Init:M/N" pod will take from the time spent since
+// StartTime on the M that are done. ok is false when the pod is not
+// initializing or no init container has completed yet.
+func EstimateInitRemaining(pod *apiv1.Pod, now time.Time) (remaining time.Duration, ok bool) {
+	status := computePodStatus(pod)
+	done := status.initContainersDone
+	if !status.initializing || done == 0 || pod.Status.StartTime == nil {
+		return 0, false
+	}
+
+	elapsed := now.Sub(pod.Status.StartTime.Time)
+	perContainer := elapsed / time.Duration(done)
+	return perContainer * time.Duration(len(pod.Spec.InitContainers)-done), true
+}
+
+// lastTermination returns the LastTerminationState of container, or nil when
+// it never terminated. The API only keeps the most recent termination of each
+// container, so earlier restarts cannot be observed: everything built on it
+// sees at most one restart per container.
+func lastTermination(container apiv1.ContainerStatus) *apiv1.ContainerStateTerminated {
+	return container.LastTerminationState.Terminated
+}
+
+// Availability estimates the fraction of the window ending at now during which
+// every container of pod was running. A running container is considered down
+// between its lastTermination and its current StartedAt, and a container that
+// is not running is considered down since it last finished (or since the
+// window started when that is unknown). Earlier restarts inside the window are
+// not observable, making the result an upper bound. The window is clipped to
+// the pod's lifetime.
+func Availability(pod *apiv1.Pod, window time.Duration, now time.Time) float64 {
+	start := now.Add(-window)
+	if pod.CreationTimestamp.After(start) {
+		start = pod.CreationTimestamp.Time
+	}
+	span := now.Sub(start)
+	if span <= 0 {
+		return 1
+	}
+
+	var worst time.Duration
+	for _, container := range pod.Status.ContainerStatuses {
+		var downFrom, downTo time.Time
+		switch {
+		case container.State.Running != nil:
+			if lastTermination(container) == nil {
+				continue
+			}
+			downFrom = lastTermination(container).FinishedAt.Time
+			downTo = container.State.Running.StartedAt.Time
+		case container.State.Terminated != nil:
+			downFrom = container.State.Terminated.FinishedAt.Time
+			downTo = now
+		case lastTermination(container) != nil:
+			downFrom = lastTermination(container).FinishedAt.Time
+			downTo = now
+		default:
+			downFrom = start
+			downTo = now
+		}
+		if downFrom.Before(start) {
+			downFrom = start
+		}
+		if down := downTo.Sub(downFrom); down > worst {
+			worst = down
+		}
+	}
+
+	if worst > span {
+		worst = span
+	}
+	return 1 - float64(worst)/float64(span)
+}
+
+// RestartsInWindow approximates how many restarts happened within window of
+// now from the lastTermination of each container, so a container contributes
+// at most one restart, making this a lower bound for containers that
+// restarted several times in the window. Use RestartsSince
+// with a previously observed count when an exact number is needed.
+func RestartsInWindow(pod *apiv1.Pod, window time.Duration, now time.Time) int {
+	restarts := 0
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, container := range statuses {
+			terminated := lastTermination(container)
+			if container.RestartCount > 0 && terminated != nil && now.Sub(terminated.FinishedAt.Time) <= window {
+				restarts++
+			}
+		}
+	}
+	return restarts
+}
+
+// OldestRestartAge returns how long ago the earliest observable restart of
+// pod happened: the age of the oldest lastTermination of its containers. ok is
+// false when no container restarted.
+func OldestRestartAge(pod *apiv1.Pod, now time.Time) (age time.Duration, ok bool) {
+	var oldest time.Time
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, container := range statuses {
+			terminated := lastTermination(container)
+			if terminated == nil || terminated.FinishedAt.IsZero() {
+				continue
+			}
+			if oldest.IsZero() || terminated.FinishedAt.Time.Before(oldest) {
+				oldest = terminated.FinishedAt.Time
+			}
+		}
+	}
+	if oldest.IsZero() {
+		return 0, false
+	}
+	return now.Sub(oldest), true
+}
+
+// EstimatedDowntime approximates how long the containers of pod were down
+// between their last termination and their restart: the gap from the
+// FinishedAt of its lastTermination to the StartedAt of the current state, or
+// to now for a container still waiting to restart. Gaps of several containers
+// are merged where they overlap, so time during which any container was down
+// counts once. Earlier gaps are missed, so this is a lower bound.
+func EstimatedDowntime(pod *apiv1.Pod, now time.Time) time.Duration {
+	var gaps [][2]time.Time
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, container := range statuses {
+			last := lastTermination(container)
+			if last == nil || last.FinishedAt.IsZero() {
+				continue
+			}
+			var restarted time.Time
+			switch {
+			case container.State.Running != nil:
+				restarted = container.State.Running.StartedAt.Time
+			case container.State.Terminated != nil:
+				restarted = container.State.Terminated.StartedAt.Time
+			case container.State.Waiting != nil:
+				restarted = now
+			}
+			if !restarted.IsZero() && restarted.After(last.FinishedAt.Time) {
+				gaps = append(gaps, [2]time.Time{last.FinishedAt.Time, restarted})
+			}
+		}
+	}
+
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i][0].Before(gaps[j][0]) })
+	var downtime time.Duration
+	var covered time.Time
+	for _, gap := range gaps {
+		if gap[0].Before(covered) {
+			gap[0] = covered
+		}
+		if gap[1].After(gap[0]) {
+			downtime += gap[1].Sub(gap[0])
+			covered = gap[1]
+		}
+	}
+	return downtime
+}
+
+// RestartsSince returns how many restarts pod had since a baseline total
+// restart count observed earlier, e.g. at the start of the window.
+func RestartsSince(pod *apiv1.Pod, baseline int) int {
+	restarts := computePodStatus(pod).restarts - baseline
+	if restarts < 0 {
+		// the counters reset, e.g. the pod was recreated under the same name
+		return 0
+	}
+	return restarts
+}
+
+// RestartCause returns why the most recently terminated container of pod last
+// exited, e.g. "OOMKilled" or "Error", or "" when no container restarted.
+func RestartCause(pod *apiv1.Pod) string {
+	var latest *apiv1.ContainerStateTerminated
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, container := range statuses {
+			terminated := lastTermination(container)
+			if container.RestartCount == 0 || terminated == nil {
+				continue
+			}
+			if latest == nil || terminated.FinishedAt.After(latest.FinishedAt.Time) {
+				latest = terminated
+			}
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return terminatedReason(latest)
+}
+
+// maxRestartsPerMinute is the sustained restart rate above which a pod is in a
+// tight crash loop: more than one restart every ten seconds.
+const maxRestartsPerMinute = 6
+
+// ImpossiblyHighRestartRate reports whether pod restarted more than
+// maxRestartsPerMinute times per minute of its age on average.
+func ImpossiblyHighRestartRate(pod *apiv1.Pod, now time.Time) bool {
+	if pod.CreationTimestamp.IsZero() {
+		return false
+	}
+	age := now.Sub(pod.CreationTimestamp.Time)
+	if age <= 0 {
+		return false
+	}
+	return float64(computePodStatus(pod).restarts)/age.Minutes() > maxRestartsPerMinute
+}
+
+// WillRestart reports whether the kubelet will restart an exited container of
+// pod: under the Always restart policy any exit restarts it, under OnFailure
+// only a non-zero exit does. Init containers run to completion, so they are
+// only retried on failure, except for native sidecars which always restart.
+// Nothing restarts once the pod reached a terminal phase.
+func WillRestart(pod *apiv1.Pod) bool {
+	if isPodPhaseTerminal(pod.Status.Phase) {
+		return false
+	}
+	policy := pod.Spec.RestartPolicy
+	if policy == "" {
+		policy = apiv1.RestartPolicyAlways
+	}
+
+	initContainers := make(map[string]*apiv1.Container)
+	for i := range pod.Spec.InitContainers {
+		initContainers[pod.Spec.InitContainers[i].Name] = &pod.Spec.InitContainers[i]
+	}
+	for _, container := range pod.Status.InitContainerStatuses {
+		initPolicy := policy
+		switch {
+		case isRestartableInitContainer(initContainers[container.Name]):
+			initPolicy = apiv1.RestartPolicyAlways
+		case policy == apiv1.RestartPolicyAlways:
+			initPolicy = apiv1.RestartPolicyOnFailure
+		}
+		if exitedAndRestarts(container, initPolicy) {
+			return true
+		}
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		if exitedAndRestarts(container, policy) {
+			return true
+		}
+	}
+	return false
+}
+
+func exitedAndRestarts(container apiv1.ContainerStatus, policy apiv1.RestartPolicy) bool {
+	terminated := container.State.Terminated
+	if terminated == nil && container.State.Waiting != nil {
+		// waiting out a back-off before the next restart
+		terminated = lastTermination(container)
+	}
+	if terminated == nil {
+		return false
+	}
+	switch policy {
+	case apiv1.RestartPolicyAlways:
+		return true
+	case apiv1.RestartPolicyOnFailure:
+		return terminated.ExitCode != 0
+	default:
+		return false
+	}
+}
+
+// AllHealthy reports whether every pod has a healthy reason (Running,
+// Completed or Succeeded) and is Ready, returning the names of the pods that
+// are not. Pods that ran to completion report Ready False by design, so the
+// Ready condition is only checked for pods still running.
+func AllHealthy(pods *apiv1.PodList) (bool, []string) {
+	var unhealthy []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		reason := computePodStatus(pod).reason
+		healthy := healthyReasons[reason]
+		if healthy && reason == "Running" {
+			for _, condition := range pod.Status.Conditions {
+				if condition.Type == apiv1.PodReady && condition.Status != apiv1.ConditionTrue {
+					healthy = false
+				}
+			}
+		}
+		if !healthy {
+			unhealthy = append(unhealthy, pod.Name)
+		}
+	}
+	return len(unhealthy) == 0, unhealthy
+}
+
+// IsFlapping reports whether any init or regular container of pod terminated
+// within the last within, even if it is running again by now.
+func IsFlapping(pod *apiv1.Pod, within time.Duration) bool {
+	return isFlapping(pod, within, time.Now())
+}
+
+func isFlapping(pod *apiv1.Pod, within time.Duration, now time.Time) bool {
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, container := range statuses {
+			terminated := lastTermination(container)
+			if terminated != nil && !terminated.FinishedAt.IsZero() && terminated.FinishedAt.After(now.Add(-within)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RunningButAllUnready reports whether pod is in phase Running while none of
+// its containers is Ready and none has completed, which usually means their
+// readiness probes are failing. A pod without container statuses yet is not
+// reported.
+func RunningButAllUnready(pod *apiv1.Pod) bool {
+	if pod.Status.Phase != apiv1.PodRunning || len(pod.Status.ContainerStatuses) == 0 {
+		return false
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Ready {
+			return false
+		}
+		if terminated := container.State.Terminated; terminated != nil && terminated.ExitCode == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadinessTrend returns, for each snapshot in order, the fraction of its pods
+// with a True Ready condition. An empty snapshot counts as 0.
+func ReadinessTrend(snapshots [][]apiv1.Pod) []float64 {
+	trend := make([]float64, len(snapshots))
+	for i, pods := range snapshots {
+		if len(pods) == 0 {
+			continue
+		}
+		ready := 0
+		for _, pod := range pods {
+			if hasPodReadyCondition(pod.Status.Conditions) {
+				ready++
+			}
+		}
+		trend[i] = float64(ready) / float64(len(pods))
+	}
+	return trend
+}
+
+// ReadyStableFor returns how long the Ready condition of pod has held its
+// current value, from its LastTransitionTime. A short duration while Ready is
+// True suggests a recent recovery. ok is false when the pod has no Ready
+// condition with a transition time.
+func ReadyStableFor(pod *apiv1.Pod, now time.Time) (time.Duration, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != apiv1.PodReady {
+			continue
+		}
+		if condition.LastTransitionTime.IsZero() {
+			return 0, false
+		}
+		return now.Sub(condition.LastTransitionTime.Time), true
+	}
+	return 0, false
+}
+
+// SingleReplicaOwners returns the controllers owning exactly one of pods, as
+// sorted "<namespace>/<kind>/<name>" keys. Such workloads have no redundancy.
+// Pods without a controller are ignored.
+func SingleReplicaOwners(pods []apiv1.Pod) []string {
+	replicas := map[string]int{}
+	for i := range pods {
+		if owner := metav1.GetControllerOf(&pods[i]); owner != nil {
+			replicas[pods[i].Namespace+"/"+owner.Kind+"/"+owner.Name]++
+		}
+	}
+	var owners []string
+	for owner, count := range replicas {
+		if count == 1 {
+			owners = append(owners, owner)
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// TopRestartingContainer returns the container with the most restarts across
+// pods, init containers included. Ties go to the lowest pod name, then the
+// lowest container name.
+func TopRestartingContainer(pods []apiv1.Pod) (podName, container string, restarts int32) {
+	found := false
+	for i := range pods {
+		pod := &pods[i]
+		for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, status := range statuses {
+				better := !found || status.RestartCount > restarts ||
+					status.RestartCount == restarts && (pod.Name < podName || pod.Name == podName && status.Name < container)
+				if better {
+					podName, container, restarts = pod.Name, status.Name, status.RestartCount
+					found = true
+				}
+			}
+		}
+	}
+	return podName, container, restarts
+}
+
+// EscalationOptions tunes when a transient status is escalated to "stuck".
+type EscalationOptions struct {
+	// StuckAfter is how old a pod may get while Pending or
+	// ContainerCreating before it is reported as stuck.
+	StuckAfter time.Duration
+}
+
+var DefaultEscalationOptions = EscalationOptions{
+	StuckAfter: 10 * time.Minute,
+}
+
+// EscalatedStatus returns the status of pod, with " (stuck)" appended when the
+// pod has been Pending or ContainerCreating for longer than
+// DefaultEscalationOptions allows.
+func EscalatedStatus(pod *apiv1.Pod, now time.Time) string {
+	return DefaultEscalationOptions.EscalatedStatus(pod, now)
+}
+
+// EscalatedStatus returns the status of pod, with " (stuck)" appended when the
+// pod has been Pending or ContainerCreating for longer than StuckAfter.
+func (o EscalationOptions) EscalatedStatus(pod *apiv1.Pod, now time.Time) string {
+	reason := printReason(pod)
+	switch reason {
+	case "Pending", "ContainerCreating":
+		if !pod.CreationTimestamp.IsZero() && now.Sub(pod.CreationTimestamp.Time) > o.StuckAfter {
+			return reason + " (stuck)"
+		}
+	}
+	return reason
+}
+
+// IsStuck reports whether pod has been in a pending-class reason, e.g.
+// Pending, ContainerCreating or Init:1/3, for longer than threshold. The time
+// is measured from the last transition of the condition that would move the
+// pod on, falling back to its creation time.
+func IsStuck(pod *apiv1.Pod, now time.Time, threshold time.Duration) bool {
+	reason := printReason(pod)
+	if ClassifyReason(reason) != Pending {
+		return false
+	}
+
+	conditionType := apiv1.PodScheduled
+	switch {
+	case strings.HasPrefix(reason, "Init:"):
+		conditionType = apiv1.PodInitialized
+	case reason == "ContainerCreating" || reason == "PodInitializing":
+		conditionType = apiv1.ContainersReady
+	}
+	since := pod.CreationTimestamp.Time
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType && !condition.LastTransitionTime.IsZero() {
+			since = condition.LastTransitionTime.Time
+		}
+	}
+	if since.IsZero() {
+		return false
+	}
+	return now.Sub(since) > threshold
+}
+
+// TerminatingOnUnreachableNode reports whether pod is being deleted while its
+// node is in unreachableNodes. The kubelet cannot confirm the deletion then,
+// so the pod stays Terminating until it is force-deleted.
+func TerminatingOnUnreachableNode(pod *apiv1.Pod, unreachableNodes map[string]bool) bool {
+	return pod.DeletionTimestamp != nil && !isPodPhaseTerminal(pod.Status.Phase) &&
+		pod.Spec.NodeName != "" && unreachableNodes[pod.Spec.NodeName]
+}
+
+// recentRestartWindow is how long ago a container may have last terminated to
+// still count as having restarted recently.
+const recentRestartWindow = 10 * time.Minute
+
+// AllProbesFailing returns the containers that declare a readiness or a
+// liveness probe and look like every probe they declare is failing: a
+// readiness probe when the container is not Ready, a liveness probe when it
+// was restarted within the last ten minutes.
+func AllProbesFailing(pod *apiv1.Pod) []string {
+	return allProbesFailing(pod, time.Now())
+}
+
+func allProbesFailing(pod *apiv1.Pod, now time.Time) []string {
+	specs := make(map[string]apiv1.Container)
+	for _, container := range pod.Spec.Containers {
+		specs[container.Name] = container
+	}
+
+	var names []string
+	for _, container := range pod.Status.ContainerStatuses {
+		spec := specs[container.Name]
+		if spec.ReadinessProbe == nil && spec.LivenessProbe == nil {
+			continue
+		}
+		if spec.ReadinessProbe != nil && container.Ready {
+			continue
+		}
+		if spec.LivenessProbe != nil {
+			terminated := lastTermination(container)
+			if container.RestartCount == 0 || terminated == nil || now.Sub(terminated.FinishedAt.Time) > recentRestartWindow {
+				continue
+			}
+		}
+		names = append(names, container.Name)
+	}
+	return names
+}
+
+// OldestUnready returns the pod without a True Ready condition that has existed
+// the longest, and its age. Pods in a terminal phase are ignored.
+func OldestUnready(pods []apiv1.Pod, now time.Time) (*apiv1.Pod, time.Duration, bool) {
+	var oldest *apiv1.Pod
+	var oldestAge time.Duration
+	for i := range pods {
+		pod := &pods[i]
+		if isPodPhaseTerminal(pod.Status.Phase) || hasPodReadyCondition(pod.Status.Conditions) {
+			continue
+		}
+		if age := now.Sub(pod.CreationTimestamp.Time); oldest == nil || age > oldestAge {
+			oldest, oldestAge = pod, age
+		}
+	}
+	return oldest, oldestAge, oldest != nil
+}
+
+// RecentlyBecameUnready returns the pods whose Ready condition turned False
+// within the last within, for churn alerts. The transition usually means the
+// pod was ready before, though a pod that just started also transitions to
+// False once. Pods in a terminal phase are ignored.
+func RecentlyBecameUnready(pods []apiv1.Pod, within time.Duration, now time.Time) []apiv1.Pod {
+	var unready []apiv1.Pod
+	for _, pod := range pods {
+		if isPodPhaseTerminal(pod.Status.Phase) {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type != apiv1.PodReady || condition.Status != apiv1.ConditionFalse || condition.LastTransitionTime.IsZero() {
+				continue
+			}
+			if since := now.Sub(condition.LastTransitionTime.Time); since >= 0 && since <= within {
+				unready = append(unready, pod)
+			}
+			break
+		}
+	}
+	return unready
+}
+
+// InitTimeFraction returns the share of the pod's age spent before it became
+// Initialized. ok is false until the Initialized condition is True, and when
+// the condition does not record when it became True.
+func InitTimeFraction(pod *apiv1.Pod) (float64, bool) {
+	return initTimeFraction(pod, time.Now())
+}
+
+func initTimeFraction(pod *apiv1.Pod, now time.Time) (float64, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != apiv1.PodInitialized || condition.Status != apiv1.ConditionTrue {
+			continue
+		}
+		if condition.LastTransitionTime.IsZero() {
+			return 0, false
+		}
+		age := now.Sub(pod.CreationTimestamp.Time)
+		if age <= 0 {
+			return 0, false
+		}
+		initTime := condition.LastTransitionTime.Sub(pod.CreationTimestamp.Time)
+		return float64(initTime) / float64(age), true
+	}
+	return 0, false
+}
+
+// HealthScoreOptions weighs the signals HealthPoints combines. The weights are
+// relative, so only their ratios matter.
+type HealthScoreOptions struct {
+	// ReadyWeight scores the fraction of containers that are Ready.
+	ReadyWeight int
+	// RestartWeight scores the absence of restarts: while the last restart is
+	// within RestartWindow, the restart count takes it down to zero at
+	// RestartSaturation restarts.
+	RestartWeight     int
+	RestartWindow     time.Duration
+	RestartSaturation int
+	// BadStateWeight scores how briefly the pod has been in a non-healthy
+	// status, reaching zero after BadStateSaturation.
+	BadStateWeight     int
+	BadStateSaturation time.Duration
+}
+
+var DefaultHealthScoreOptions = HealthScoreOptions{
+	ReadyWeight:        50,
+	RestartWeight:      30,
+	RestartWindow:      time.Hour,
+	RestartSaturation:  5,
+	BadStateWeight:     20,
+	BadStateSaturation: time.Hour,
+}
+
+// HealthPoints scores pod from 0 (worst) to 100 (healthy) using
+// DefaultHealthScoreOptions, to sort a triage view by.
+func HealthPoints(pod *apiv1.Pod, now time.Time) int {
+	return DefaultHealthScoreOptions.HealthPoints(pod, now)
+}
+
+// HealthPoints scores pod from 0 (worst) to 100 (healthy) by combining its
+// readiness, its recent restarts and how long it has been in a non-healthy
+// status, weighted by o. Succeeded pods count as fully ready.
+func (o HealthScoreOptions) HealthPoints(pod *apiv1.Pod, now time.Time) int {
+	total := o.ReadyWeight + o.RestartWeight + o.BadStateWeight
+	if total <= 0 {
+		return 100
+	}
+
+	status := computePodStatus(pod)
+	ready := 1.0
+	if pod.Status.Phase != apiv1.PodSucceeded && status.totalContainers > 0 {
+		ready = float64(status.readyContainers) / float64(status.totalContainers)
+	}
+
+	restarts := 0.0
+	if o.RestartSaturation > 0 && status.restarts > 0 && now.Sub(status.lastRestartDate.Time) <= o.RestartWindow {
+		restarts = math.Min(1, float64(status.restarts)/float64(o.RestartSaturation))
+	}
+
+	badState := 0.0
+	if ClassifyReason(printReason(pod)) != Healthy {
+		since := pod.CreationTimestamp.Time
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == apiv1.PodReady && !condition.LastTransitionTime.IsZero() {
+				since = condition.LastTransitionTime.Time
+			}
+		}
+		switch {
+		case o.BadStateSaturation <= 0:
+			badState = 1
+		case !since.IsZero():
+			badState = math.Min(1, math.Max(0, float64(now.Sub(since))/float64(o.BadStateSaturation)))
+		}
+	}
+
+	score := float64(o.ReadyWeight)*ready + float64(o.RestartWeight)*(1-restarts) + float64(o.BadStateWeight)*(1-badState)
+	return int(math.Round(100 * score / float64(total)))
+}
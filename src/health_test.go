@@ -0,0 +1,1365 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsCrashLooping(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := CrashLoopOptions{RestartThreshold: 3, Window: 5 * time.Minute}
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect bool
+	}{
+		{
+			// Test healthy running container
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test1"},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			false,
+		},
+		{
+			// Test container in CrashLoopBackOff
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test2"},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{RestartCount: 1, State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+					},
+				},
+			},
+			true,
+		},
+		{
+			// Test init container in CrashLoopBackOff
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test3"},
+				Status: apiv1.PodStatus{
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{RestartCount: 1, State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+					},
+				},
+			},
+			true,
+		},
+		{
+			// Test many restarts with a recent termination
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test4"},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount:         4,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+						},
+					},
+				},
+			},
+			true,
+		},
+		{
+			// Test many restarts but the last termination is old
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test5"},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount:         4,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Hour))}},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			// Test recent termination below the restart threshold
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test6"},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount:         2,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+						},
+					},
+				},
+			},
+			false,
+		},
+	}
+
+	for i, test := range tests {
+		crashLooping := opts.IsCrashLooping(&test.pod, now)
+		if !reflect.DeepEqual(test.expect, crashLooping) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, crashLooping))
+		}
+	}
+}
+
+func TestContainerReadyAges(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test1"},
+		Status: apiv1.PodStatus{
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{Name: "app", Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-5 * time.Minute))}}},
+				{Name: "sidecar", State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-30 * time.Second))}}},
+				{Name: "waiting", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			},
+		},
+	}
+
+	expect := map[string]time.Duration{
+		"app":     5 * time.Minute,
+		"sidecar": 30 * time.Second,
+	}
+	ages := ContainerReadyAges(&pod, now)
+	if !reflect.DeepEqual(expect, ages) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, ages))
+	}
+}
+
+func TestAvailableReplicas(t *testing.T) {
+	deleted := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ready := []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionTrue}}
+	notReady := []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionFalse}}
+
+	pods := []apiv1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ready1"}, Status: apiv1.PodStatus{Conditions: ready}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ready2"}, Status: apiv1.PodStatus{Conditions: ready}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "terminating", DeletionTimestamp: &deleted}, Status: apiv1.PodStatus{Conditions: ready}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unready"}, Status: apiv1.PodStatus{Conditions: notReady}},
+	}
+
+	if available := AvailableReplicas(pods); available != 2 {
+		t.Errorf("mismatch: %s", cmp.Diff(2, available))
+	}
+	if available := AvailableReplicas(nil); available != 0 {
+		t.Errorf("mismatch: %s", cmp.Diff(0, available))
+	}
+}
+
+func TestReplicasMet(t *testing.T) {
+	deleted := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	pod := func(ready bool, deletionTimestamp *metav1.Time) apiv1.Pod {
+		status := apiv1.ConditionFalse
+		if ready {
+			status = apiv1.ConditionTrue
+		}
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: deletionTimestamp},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             apiv1.PodRunning,
+				Conditions:        []apiv1.PodCondition{{Type: apiv1.PodReady, Status: status}},
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: ready, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		}
+	}
+	pods := []apiv1.Pod{pod(true, nil), pod(true, nil), pod(false, nil), pod(true, &deleted)}
+
+	tests := []struct {
+		expected int
+		expect   bool
+	}{
+		{0, true},
+		{2, true},
+		{3, false},
+		{4, false},
+	}
+
+	for i, test := range tests {
+		if got := ReplicasMet(pods, test.expected); got != test.expect {
+			t.Errorf("%d mismatch: got %v, expected %v", i, got, test.expect)
+		}
+	}
+}
+
+func TestFailureSignatures(t *testing.T) {
+	pullFailure := func(name, uid string) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: apiv1.PodStatus{
+				Phase: apiv1.PodPending,
+				ContainerStatuses: []apiv1.ContainerStatus{
+					{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: "Back-off pulling image \"registry/app:v2\" for pod " + name + " (" + uid + ")",
+					}}},
+				},
+			},
+		}
+	}
+
+	pods := []apiv1.Pod{
+		pullFailure("web-1", "0f8fad5b-d9cb-469f-a165-70867728950e"),
+		pullFailure("web-2", "7c9e6679-7425-40de-944b-e07fc1f90ae7"),
+		{ObjectMeta: metav1.ObjectMeta{Name: "healthy"}, Status: apiv1.PodStatus{Phase: apiv1.PodRunning}},
+		pullFailure("web-3", "16fd2706-8baf-433b-82eb-8c7fada847da"),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "crasher"},
+			Status: apiv1.PodStatus{
+				Phase: apiv1.PodRunning,
+				ContainerStatuses: []apiv1.ContainerStatus{
+					{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			},
+		},
+	}
+
+	expect := map[string][]string{
+		"ImagePullBackOff: Back-off pulling image \"registry/app:v2\" for pod <pod> (<uid>)": {"web-1", "web-2", "web-3"},
+		"CrashLoopBackOff": {"crasher"},
+	}
+	signatures := FailureSignatures(pods)
+	if !reflect.DeepEqual(expect, signatures) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, signatures))
+	}
+}
+
+func TestGateRemovalReady(t *testing.T) {
+	satisfied := map[string]bool{"example.com/quota": true, "example.com/image-scan": true}
+	predicate := func(gate string) bool { return satisfied[gate] }
+
+	tests := []struct {
+		gates  []apiv1.PodSchedulingGate
+		expect bool
+	}{
+		{nil, true},
+		{[]apiv1.PodSchedulingGate{{Name: "example.com/quota"}, {Name: "example.com/image-scan"}}, true},
+		{[]apiv1.PodSchedulingGate{{Name: "example.com/quota"}, {Name: "example.com/approval"}}, false},
+	}
+
+	for i, test := range tests {
+		pod := apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "gated"},
+			Spec:       apiv1.PodSpec{SchedulingGates: test.gates},
+		}
+		ready := GateRemovalReady(&pod, predicate)
+		if !reflect.DeepEqual(test.expect, ready) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, ready))
+		}
+	}
+}
+
+func TestEstimateInitRemaining(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	started := metav1.NewTime(now.Add(-2 * time.Minute))
+
+	tests := []struct {
+		pod             apiv1.Pod
+		expectRemaining time.Duration
+		expectOK        bool
+	}{
+		{
+			// Test pod at Init:1/2 after two minutes
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test1"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 2), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					StartTime: &started,
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{}}},
+						{State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			2 * time.Minute,
+			true,
+		},
+		{
+			// Test pod at Init:0/2 has no progress to extrapolate from
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test2"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 2), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					StartTime: &started,
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+						{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{}}},
+					},
+				},
+			},
+			0,
+			false,
+		},
+		{
+			// Test initialized pod
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test3"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 1), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					StartTime: &started,
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{}}},
+					},
+				},
+			},
+			0,
+			false,
+		},
+	}
+
+	for i, test := range tests {
+		remaining, ok := EstimateInitRemaining(&test.pod, now)
+		if test.expectOK != ok || test.expectRemaining != remaining {
+			t.Errorf("%d mismatch: got (%v, %v), expected (%v, %v)", i, remaining, ok, test.expectRemaining, test.expectOK)
+		}
+	}
+}
+
+func TestAvailability(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-24 * time.Hour))
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect float64
+	}{
+		{
+			// Test never restarted container
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test1", CreationTimestamp: created},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{StartedAt: created}}},
+					},
+				},
+			},
+			1,
+		},
+		{
+			// Test container restarted 30m ago after being down for 6 minutes
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test2", CreationTimestamp: created},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount:         1,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-30 * time.Minute))}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-36 * time.Minute))}},
+						},
+					},
+				},
+			},
+			0.9,
+		},
+		{
+			// Test container down since 15m ago
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test3", CreationTimestamp: created},
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount:         3,
+							State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-15 * time.Minute))}},
+						},
+					},
+				},
+			},
+			0.75,
+		},
+	}
+
+	for i, test := range tests {
+		availability := Availability(&test.pod, time.Hour, now)
+		if math.Abs(test.expect-availability) > 1e-9 {
+			t.Errorf("%d mismatch: got %v, expected %v", i, availability, test.expect)
+		}
+	}
+}
+
+func TestRestartsInWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test1"},
+		Status: apiv1.PodStatus{
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{
+					Name:                 "recent",
+					RestartCount:         4,
+					State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+					LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-10 * time.Minute))}},
+				},
+				{
+					Name:                 "old",
+					RestartCount:         1,
+					State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+					LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-3 * time.Hour))}},
+				},
+			},
+		},
+	}
+
+	if restarts := RestartsInWindow(&pod, time.Hour, now); restarts != 1 {
+		t.Errorf("RestartsInWindow mismatch: %s", cmp.Diff(1, restarts))
+	}
+	if restarts := RestartsSince(&pod, 2); restarts != 3 {
+		t.Errorf("RestartsSince mismatch: %s", cmp.Diff(3, restarts))
+	}
+	if restarts := RestartsSince(&pod, 9); restarts != 0 {
+		t.Errorf("RestartsSince after reset mismatch: %s", cmp.Diff(0, restarts))
+	}
+}
+
+func TestRestartCause(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect string
+	}{
+		{
+			// Test never restarted
+			apiv1.Pod{
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+				},
+			},
+			"",
+		},
+		{
+			// Test the most recent termination wins
+			apiv1.Pod{
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount:         2,
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-time.Hour))}},
+						},
+						{
+							RestartCount:         1,
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137, FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+						},
+					},
+				},
+			},
+			"OOMKilled",
+		},
+		{
+			// Test init container restart without a reason
+			apiv1.Pod{
+				Status: apiv1.PodStatus{
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount:         1,
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 2, FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+						},
+					},
+				},
+			},
+			"ExitCode:2",
+		},
+	}
+
+	for i, test := range tests {
+		cause := RestartCause(&test.pod)
+		if !reflect.DeepEqual(test.expect, cause) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, cause))
+		}
+	}
+}
+
+func TestImpossiblyHighRestartRate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(restarts int32, age time.Duration) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             "Running",
+				ContainerStatuses: []apiv1.ContainerStatus{{RestartCount: restarts, State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect bool
+	}{
+		// Test 30 restarts in 2 minutes
+		{pod(30, 2*time.Minute), true},
+		// Test exactly one restart every ten seconds
+		{pod(12, 2*time.Minute), false},
+		// Test 30 restarts over a day
+		{pod(30, 24*time.Hour), false},
+		// Test no restarts
+		{pod(0, time.Second), false},
+		// Test unknown creation time
+		{apiv1.Pod{Status: apiv1.PodStatus{ContainerStatuses: []apiv1.ContainerStatus{{RestartCount: 30}}}}, false},
+	}
+
+	for i, test := range tests {
+		high := ImpossiblyHighRestartRate(&test.pod, now)
+		if !reflect.DeepEqual(test.expect, high) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, high))
+		}
+	}
+}
+
+func TestWillRestart(t *testing.T) {
+	exited := func(exitCode int32) apiv1.ContainerStatus {
+		return apiv1.ContainerStatus{Name: "app", State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: exitCode}}}
+	}
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect bool
+	}{
+		{
+			// Test Always restarts a container that exited cleanly
+			apiv1.Pod{
+				Spec:   apiv1.PodSpec{RestartPolicy: apiv1.RestartPolicyAlways},
+				Status: apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: []apiv1.ContainerStatus{exited(0)}},
+			},
+			true,
+		},
+		{
+			// Test OnFailure restarts a container that exited with 1
+			apiv1.Pod{
+				Spec:   apiv1.PodSpec{RestartPolicy: apiv1.RestartPolicyOnFailure},
+				Status: apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: []apiv1.ContainerStatus{exited(1)}},
+			},
+			true,
+		},
+		{
+			// Test OnFailure does not restart a container that exited with 0
+			apiv1.Pod{
+				Spec:   apiv1.PodSpec{RestartPolicy: apiv1.RestartPolicyOnFailure},
+				Status: apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: []apiv1.ContainerStatus{exited(0)}},
+			},
+			false,
+		},
+		{
+			// Test Never does not restart a failed container
+			apiv1.Pod{
+				Spec:   apiv1.PodSpec{RestartPolicy: apiv1.RestartPolicyNever},
+				Status: apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: []apiv1.ContainerStatus{exited(1)}},
+			},
+			false,
+		},
+		{
+			// Test container backing off under the default policy
+			apiv1.Pod{
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{{
+						State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 1}},
+					}},
+				},
+			},
+			true,
+		},
+		{
+			// Test completed init container is not restarted under Always
+			apiv1.Pod{
+				Spec: apiv1.PodSpec{RestartPolicy: apiv1.RestartPolicyAlways, InitContainers: []apiv1.Container{{Name: "app"}}},
+				Status: apiv1.PodStatus{
+					Phase:                 apiv1.PodPending,
+					InitContainerStatuses: []apiv1.ContainerStatus{exited(0)},
+				},
+			},
+			false,
+		},
+		{
+			// Test exited sidecar restarts even under Never
+			apiv1.Pod{
+				Spec: apiv1.PodSpec{RestartPolicy: apiv1.RestartPolicyNever, InitContainers: []apiv1.Container{{Name: "app", RestartPolicy: &containerRestartPolicyAlways}}},
+				Status: apiv1.PodStatus{
+					Phase:                 apiv1.PodRunning,
+					InitContainerStatuses: []apiv1.ContainerStatus{exited(0)},
+				},
+			},
+			true,
+		},
+		{
+			// Test failed pod is not restarted
+			apiv1.Pod{
+				Spec:   apiv1.PodSpec{RestartPolicy: apiv1.RestartPolicyOnFailure},
+				Status: apiv1.PodStatus{Phase: apiv1.PodFailed, ContainerStatuses: []apiv1.ContainerStatus{exited(1)}},
+			},
+			false,
+		},
+	}
+
+	for i, test := range tests {
+		restart := WillRestart(&test.pod)
+		if !reflect.DeepEqual(test.expect, restart) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, restart))
+		}
+	}
+}
+
+func TestAllHealthy(t *testing.T) {
+	running := func(name string, ready apiv1.ConditionStatus) apiv1.Pod {
+		pod := apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             apiv1.PodRunning,
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: ready == apiv1.ConditionTrue, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		}
+		if ready != "" {
+			pod.Status.Conditions = []apiv1.PodCondition{{Type: apiv1.PodReady, Status: ready}}
+		}
+		return pod
+	}
+	completed := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job"},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase:      apiv1.PodSucceeded,
+			Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionFalse, Reason: "PodCompleted"}},
+		},
+	}
+	crashing := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase:             apiv1.PodRunning,
+			ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+		},
+	}
+
+	tests := []struct {
+		pods          []apiv1.Pod
+		expectHealthy bool
+		expectNames   []string
+	}{
+		{nil, true, nil},
+		{[]apiv1.Pod{running("web", apiv1.ConditionTrue), running("cache", ""), completed}, true, nil},
+		{[]apiv1.Pod{running("web", apiv1.ConditionTrue), crashing, running("cache", apiv1.ConditionFalse)}, false, []string{"api", "cache"}},
+	}
+
+	for i, test := range tests {
+		healthy, names := AllHealthy(&apiv1.PodList{Items: test.pods})
+		if healthy != test.expectHealthy || !reflect.DeepEqual(test.expectNames, names) {
+			t.Errorf("%d mismatch: got (%v, %v), expected (%v, %v)", i, healthy, names, test.expectHealthy, test.expectNames)
+		}
+	}
+}
+
+func TestIsFlapping(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	restartedAgo := func(d time.Duration) apiv1.Pod {
+		return apiv1.Pod{
+			Spec: apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase: apiv1.PodRunning,
+				ContainerStatuses: []apiv1.ContainerStatus{
+					{
+						Ready:                true,
+						RestartCount:         1,
+						State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+						LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", FinishedAt: metav1.NewTime(now.Add(-d))}},
+					},
+				},
+			},
+		}
+	}
+	recent := restartedAgo(10 * time.Second)
+	old := restartedAgo(5 * 24 * time.Hour)
+
+	tests := []struct {
+		pod          apiv1.Pod
+		expect       bool
+		expectReason string
+	}{
+		{recent, true, "Running (restarting)"},
+		{old, false, "Running"},
+		{apiv1.Pod{Status: apiv1.PodStatus{Phase: apiv1.PodPending}}, false, "Pending"},
+	}
+
+	opts := ReasonOptions{RestartingWithin: time.Minute}
+	for i, test := range tests {
+		if got := isFlapping(&test.pod, time.Minute, now); got != test.expect {
+			t.Errorf("%d mismatch: got %v, expected %v", i, got, test.expect)
+		}
+		if reason := podStatusReasonWithOptions(&test.pod, opts, now); reason != test.expectReason {
+			t.Errorf("%d mismatch: got %q, expected %q", i, reason, test.expectReason)
+		}
+	}
+
+	if reason := podStatusReasonWithOptions(&recent, ReasonOptions{}, now); reason != "Running" {
+		t.Errorf("mismatch: got %q, expected the suffix to be off by default", reason)
+	}
+}
+
+func TestRunningButAllUnready(t *testing.T) {
+	running := apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}
+	completed := apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed"}}
+
+	tests := []struct {
+		phase    apiv1.PodPhase
+		statuses []apiv1.ContainerStatus
+		expect   bool
+	}{
+		// Test running containers all failing their readiness probes
+		{apiv1.PodRunning, []apiv1.ContainerStatus{{State: running}, {State: running}}, true},
+		// Test one ready container
+		{apiv1.PodRunning, []apiv1.ContainerStatus{{State: running}, {Ready: true, State: running}}, false},
+		// Test a completed container
+		{apiv1.PodRunning, []apiv1.ContainerStatus{{State: running}, {State: completed}}, false},
+		// Test a pending pod
+		{apiv1.PodPending, []apiv1.ContainerStatus{{State: running}}, false},
+		// Test no container statuses reported yet
+		{apiv1.PodRunning, nil, false},
+	}
+
+	for i, test := range tests {
+		pod := apiv1.Pod{Status: apiv1.PodStatus{Phase: test.phase, ContainerStatuses: test.statuses}}
+		if got := RunningButAllUnready(&pod); got != test.expect {
+			t.Errorf("%d mismatch: got %v, expected %v", i, got, test.expect)
+		}
+	}
+}
+
+func TestReadinessTrend(t *testing.T) {
+	pod := func(ready apiv1.ConditionStatus) apiv1.Pod {
+		return apiv1.Pod{Status: apiv1.PodStatus{Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: ready}}}}
+	}
+	snapshots := [][]apiv1.Pod{
+		{pod(apiv1.ConditionFalse), pod(apiv1.ConditionFalse), pod(apiv1.ConditionFalse), pod(apiv1.ConditionTrue)},
+		{pod(apiv1.ConditionTrue), pod(apiv1.ConditionFalse), pod(apiv1.ConditionTrue), pod(apiv1.ConditionFalse)},
+		{pod(apiv1.ConditionTrue), pod(apiv1.ConditionTrue), pod(apiv1.ConditionTrue), pod(apiv1.ConditionTrue)},
+		{},
+	}
+
+	expect := []float64{0.25, 0.5, 1, 0}
+	trend := ReadinessTrend(snapshots)
+	if !reflect.DeepEqual(expect, trend) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, trend))
+	}
+}
+
+func TestReadyStableFor(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(conditions ...apiv1.PodCondition) apiv1.Pod {
+		return apiv1.Pod{Status: apiv1.PodStatus{Conditions: conditions}}
+	}
+
+	tests := []struct {
+		pod          apiv1.Pod
+		expectStable time.Duration
+		expectOK     bool
+	}{
+		{
+			// Test recently recovered pod
+			pod(apiv1.PodCondition{Type: apiv1.PodReady, Status: apiv1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-30 * time.Second))}),
+			30 * time.Second,
+			true,
+		},
+		{
+			// Test pod ready for days
+			pod(
+				apiv1.PodCondition{Type: apiv1.PodScheduled, Status: apiv1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-time.Minute))},
+				apiv1.PodCondition{Type: apiv1.PodReady, Status: apiv1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-72 * time.Hour))},
+			),
+			72 * time.Hour,
+			true,
+		},
+		{
+			// Test Ready condition without a transition time
+			pod(apiv1.PodCondition{Type: apiv1.PodReady, Status: apiv1.ConditionFalse}),
+			0,
+			false,
+		},
+		{
+			// Test no Ready condition
+			pod(),
+			0,
+			false,
+		},
+	}
+
+	for i, test := range tests {
+		stable, ok := ReadyStableFor(&test.pod, now)
+		if stable != test.expectStable || ok != test.expectOK {
+			t.Errorf("%d mismatch: got (%v, %v), expected (%v, %v)", i, stable, ok, test.expectStable, test.expectOK)
+		}
+	}
+}
+
+func TestOldestRestartAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	terminatedAt := func(ago time.Duration) apiv1.ContainerState {
+		return apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-ago))}}
+	}
+
+	tests := []struct {
+		pod       apiv1.Pod
+		expectAge time.Duration
+		expectOK  bool
+	}{
+		{
+			// Test the earliest termination across init and regular containers
+			apiv1.Pod{
+				Status: apiv1.PodStatus{
+					InitContainerStatuses: []apiv1.ContainerStatus{{RestartCount: 1, LastTerminationState: terminatedAt(2 * time.Hour)}},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{RestartCount: 5, LastTerminationState: terminatedAt(time.Minute)},
+						{RestartCount: 2, LastTerminationState: terminatedAt(30 * time.Minute)},
+					},
+				},
+			},
+			2 * time.Hour,
+			true,
+		},
+		{
+			// Test never restarted
+			apiv1.Pod{
+				Status: apiv1.PodStatus{
+					ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+				},
+			},
+			0,
+			false,
+		},
+	}
+
+	for i, test := range tests {
+		age, ok := OldestRestartAge(&test.pod, now)
+		if age != test.expectAge || ok != test.expectOK {
+			t.Errorf("%d mismatch: got (%v, %v), expected (%v, %v)", i, age, ok, test.expectAge, test.expectOK)
+		}
+	}
+}
+
+func TestSingleReplicaOwners(t *testing.T) {
+	pod := func(namespace, kind, owner string, controller bool) apiv1.Pod {
+		var refs []metav1.OwnerReference
+		if owner != "" {
+			isController := controller
+			refs = []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: &isController}}
+		}
+		return apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, OwnerReferences: refs}}
+	}
+	pods := []apiv1.Pod{
+		pod("prod", "ReplicaSet", "web-7d4b9c8f5", true),
+		pod("prod", "ReplicaSet", "web-7d4b9c8f5", true),
+		pod("prod", "ReplicaSet", "web-7d4b9c8f5", true),
+		pod("prod", "StatefulSet", "db", true),
+		pod("staging", "ReplicaSet", "web-7d4b9c8f5", true),
+		pod("prod", "ConfigMap", "not-a-controller", false),
+		pod("prod", "", "", false),
+	}
+
+	expect := []string{"prod/StatefulSet/db", "staging/ReplicaSet/web-7d4b9c8f5"}
+	owners := SingleReplicaOwners(pods)
+	if !reflect.DeepEqual(expect, owners) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, owners))
+	}
+}
+
+func TestTopRestartingContainer(t *testing.T) {
+	withRestarts := func(name string, restarts map[string]int32) apiv1.Pod {
+		pod := apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, container := range []string{"app", "init", "sidecar"} {
+			if count, ok := restarts[container]; ok {
+				status := apiv1.ContainerStatus{Name: container, RestartCount: count}
+				if container == "init" {
+					pod.Status.InitContainerStatuses = append(pod.Status.InitContainerStatuses, status)
+				} else {
+					pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, status)
+				}
+			}
+		}
+		return pod
+	}
+
+	tests := []struct {
+		pods            []apiv1.Pod
+		expectPod       string
+		expectContainer string
+		expectRestarts  int32
+	}{
+		{nil, "", "", 0},
+		{
+			[]apiv1.Pod{
+				withRestarts("web-1", map[string]int32{"app": 3, "sidecar": 12}),
+				withRestarts("web-2", map[string]int32{"app": 9, "init": 14}),
+			},
+			"web-2", "init", 14,
+		},
+		{
+			// Test ties are broken by pod name then container name
+			[]apiv1.Pod{
+				withRestarts("web-2", map[string]int32{"app": 7}),
+				withRestarts("web-1", map[string]int32{"sidecar": 7, "app": 7}),
+			},
+			"web-1", "app", 7,
+		},
+	}
+
+	for i, test := range tests {
+		podName, container, restarts := TopRestartingContainer(test.pods)
+		if podName != test.expectPod || container != test.expectContainer || restarts != test.expectRestarts {
+			t.Errorf("%d mismatch: got (%q, %q, %d), expected (%q, %q, %d)", i, podName, container, restarts, test.expectPod, test.expectContainer, test.expectRestarts)
+		}
+	}
+}
+
+func TestEscalatedStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := EscalationOptions{StuckAfter: 5 * time.Minute}
+	creating := []apiv1.ContainerStatus{{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ContainerCreating"}}}}
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect string
+	}{
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "fresh-pending", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+			},
+			"Pending",
+		},
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "stuck-pending", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+			},
+			"Pending (stuck)",
+		},
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "fresh-creating", CreationTimestamp: metav1.NewTime(now.Add(-30 * time.Second))},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodPending, ContainerStatuses: creating},
+			},
+			"ContainerCreating",
+		},
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "stuck-creating", CreationTimestamp: metav1.NewTime(now.Add(-6 * time.Minute))},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodPending, ContainerStatuses: creating},
+			},
+			"ContainerCreating (stuck)",
+		},
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "old-running", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodRunning},
+			},
+			"Running",
+		},
+	}
+
+	for i, test := range tests {
+		status := opts.EscalatedStatus(&test.pod, now)
+		if !reflect.DeepEqual(test.expect, status) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, status))
+		}
+	}
+}
+
+func TestIsStuck(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	creating := func(created time.Duration, conditions ...apiv1.PodCondition) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-created))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             apiv1.PodPending,
+				Conditions:        conditions,
+				ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ContainerCreating"}}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect bool
+	}{
+		{
+			// Test freshly created pending pod
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-30 * time.Second))},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+			},
+			false,
+		},
+		{
+			// Test old ContainerCreating pod
+			creating(time.Hour),
+			true,
+		},
+		{
+			// Test old pod whose containers only recently started being created
+			creating(time.Hour, apiv1.PodCondition{Type: apiv1.ContainersReady, Status: apiv1.ConditionFalse, LastTransitionTime: metav1.NewTime(now.Add(-time.Minute))}),
+			false,
+		},
+		{
+			// Test init progress stalled since initialization began
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 2), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:      apiv1.PodPending,
+					Conditions: []apiv1.PodCondition{{Type: apiv1.PodInitialized, Status: apiv1.ConditionFalse, LastTransitionTime: metav1.NewTime(now.Add(-20 * time.Minute))}},
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed"}}},
+						{State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			true,
+		},
+		{
+			// Test old crash-looping pod is broken rather than stuck
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:             apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+				},
+			},
+			false,
+		},
+	}
+
+	for i, test := range tests {
+		stuck := IsStuck(&test.pod, now, 10*time.Minute)
+		if !reflect.DeepEqual(test.expect, stuck) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, stuck))
+		}
+	}
+}
+
+func TestTerminatingOnUnreachableNode(t *testing.T) {
+	deleted := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	unreachable := map[string]bool{"node-2": true}
+	pod := func(node string, deletionTimestamp *metav1.Time, phase apiv1.PodPhase) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: deletionTimestamp},
+			Spec:       apiv1.PodSpec{NodeName: node},
+			Status:     apiv1.PodStatus{Phase: phase},
+		}
+	}
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect bool
+	}{
+		{pod("node-2", &deleted, apiv1.PodRunning), true},
+		{pod("node-1", &deleted, apiv1.PodRunning), false},
+		{pod("node-2", nil, apiv1.PodRunning), false},
+		{pod("node-2", &deleted, apiv1.PodSucceeded), false},
+		{pod("", &deleted, apiv1.PodPending), false},
+	}
+
+	for i, test := range tests {
+		if got := TerminatingOnUnreachableNode(&test.pod, unreachable); got != test.expect {
+			t.Errorf("%d mismatch: got %v, expected %v", i, got, test.expect)
+		}
+	}
+}
+
+func TestAllProbesFailing(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	probe := &apiv1.Probe{ProbeHandler: apiv1.ProbeHandler{HTTPGet: &apiv1.HTTPGetAction{Path: "/healthz"}}}
+	recently := apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-2 * time.Minute))}}
+	longAgo := apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-2 * time.Hour))}}
+	running := apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}
+
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test1"},
+		Spec: apiv1.PodSpec{
+			Containers: []apiv1.Container{
+				{Name: "failing", ReadinessProbe: probe, LivenessProbe: probe},
+				{Name: "ready", ReadinessProbe: probe, LivenessProbe: probe},
+				{Name: "old-restart", ReadinessProbe: probe, LivenessProbe: probe},
+				{Name: "readiness-only", ReadinessProbe: probe},
+				{Name: "readiness-only-ready", ReadinessProbe: probe},
+				{Name: "liveness-only", LivenessProbe: probe},
+				{Name: "liveness-only-stable", LivenessProbe: probe},
+				{Name: "unprobed"},
+			},
+		},
+		Status: apiv1.PodStatus{
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{Name: "failing", RestartCount: 4, State: running, LastTerminationState: recently},
+				{Name: "ready", Ready: true, RestartCount: 4, State: running, LastTerminationState: recently},
+				{Name: "old-restart", RestartCount: 1, State: running, LastTerminationState: longAgo},
+				{Name: "readiness-only", State: running},
+				{Name: "readiness-only-ready", Ready: true, State: running},
+				{Name: "liveness-only", Ready: true, RestartCount: 2, State: running, LastTerminationState: recently},
+				{Name: "liveness-only-stable", Ready: true, State: running},
+				{Name: "unprobed", RestartCount: 4, State: running, LastTerminationState: recently},
+			},
+		},
+	}
+
+	expect := []string{"failing", "readiness-only", "liveness-only"}
+	names := allProbesFailing(&pod, now)
+	if !reflect.DeepEqual(expect, names) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, names))
+	}
+}
+
+func TestOldestUnready(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ready := []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionTrue}}
+	pod := func(name string, age time.Duration, phase apiv1.PodPhase, conditions []apiv1.PodCondition) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Status:     apiv1.PodStatus{Phase: phase, Conditions: conditions},
+		}
+	}
+
+	pods := []apiv1.Pod{
+		pod("ready-old", 48*time.Hour, apiv1.PodRunning, ready),
+		pod("unready-young", time.Minute, apiv1.PodPending, nil),
+		pod("failed-old", 72*time.Hour, apiv1.PodFailed, nil),
+		pod("unready-old", 3*time.Hour, apiv1.PodRunning, nil),
+	}
+
+	oldest, age, ok := OldestUnready(pods, now)
+	if !ok || oldest.Name != "unready-old" || age != 3*time.Hour {
+		t.Errorf("mismatch: got (%v, %v, %v), expected (unready-old, 3h0m0s, true)", oldest, age, ok)
+	}
+
+	if _, _, ok := OldestUnready(pods[:1], now); ok {
+		t.Errorf("expected no unready pod among ready pods")
+	}
+}
+
+func TestRecentlyBecameUnready(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(name string, phase apiv1.PodPhase, status apiv1.ConditionStatus, ago time.Duration) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: apiv1.PodStatus{
+				Phase:      phase,
+				Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(now.Add(-ago))}},
+			},
+		}
+	}
+	pods := []apiv1.Pod{
+		pod("dropped", apiv1.PodRunning, apiv1.ConditionFalse, 30*time.Second),
+		pod("unready-long", apiv1.PodRunning, apiv1.ConditionFalse, time.Hour),
+		pod("ready", apiv1.PodRunning, apiv1.ConditionTrue, 30*time.Second),
+		pod("completed", apiv1.PodSucceeded, apiv1.ConditionFalse, 30*time.Second),
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-conditions"}, Status: apiv1.PodStatus{Phase: apiv1.PodPending}},
+	}
+
+	expect := []string{"dropped"}
+	if names := podNames(RecentlyBecameUnready(pods, time.Minute, now)); !reflect.DeepEqual(expect, names) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, names))
+	}
+	if unready := RecentlyBecameUnready(pods, 10*time.Second, now); len(unready) != 0 {
+		t.Errorf("expected no pod within 10s, got %v", podNames(unready))
+	}
+}
+
+func TestInitTimeFraction(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-10 * time.Minute))
+
+	initialized := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test1", CreationTimestamp: created},
+		Status: apiv1.PodStatus{
+			Conditions: []apiv1.PodCondition{
+				{Type: apiv1.PodInitialized, Status: apiv1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(4 * time.Minute))},
+			},
+		},
+	}
+	fraction, ok := initTimeFraction(&initialized, now)
+	if !ok || math.Abs(fraction-0.4) > 1e-9 {
+		t.Errorf("mismatch: got (%v, %v), expected (0.4, true)", fraction, ok)
+	}
+
+	initializing := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test2", CreationTimestamp: created},
+		Status: apiv1.PodStatus{
+			Conditions: []apiv1.PodCondition{
+				{Type: apiv1.PodInitialized, Status: apiv1.ConditionFalse, LastTransitionTime: created},
+			},
+		},
+	}
+	if _, ok := initTimeFraction(&initializing, now); ok {
+		t.Errorf("expected no fraction for a pod not yet initialized")
+	}
+
+	unknown := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test3", CreationTimestamp: created},
+		Status: apiv1.PodStatus{
+			Conditions: []apiv1.PodCondition{
+				{Type: apiv1.PodInitialized, Status: apiv1.ConditionTrue},
+			},
+		},
+	}
+	if fraction, ok := initTimeFraction(&unknown, now); ok || fraction != 0 {
+		t.Errorf("mismatch: got (%v, %v), expected (0, false) without a transition time", fraction, ok)
+	}
+}
+
+func TestEstimatedDowntime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	terminatedAt := func(d time.Duration) apiv1.ContainerState {
+		return apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-d))}}
+	}
+	runningSince := func(d time.Duration) apiv1.ContainerState {
+		return apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-d))}}
+	}
+
+	tests := []struct {
+		statuses []apiv1.ContainerStatus
+		expect   time.Duration
+	}{
+		// Test a container restarted 90s after it crashed
+		{[]apiv1.ContainerStatus{{RestartCount: 1, State: runningSince(10 * time.Minute), LastTerminationState: terminatedAt(10*time.Minute + 90*time.Second)}}, 90 * time.Second},
+		// Test a container still waiting to restart
+		{[]apiv1.ContainerStatus{{RestartCount: 3, State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}, LastTerminationState: terminatedAt(time.Minute)}}, time.Minute},
+		// Test disjoint gaps of several containers are summed
+		{
+			[]apiv1.ContainerStatus{
+				{RestartCount: 1, State: runningSince(time.Hour), LastTerminationState: terminatedAt(time.Hour + 30*time.Second)},
+				{RestartCount: 1, State: runningSince(10 * time.Minute), LastTerminationState: terminatedAt(10*time.Minute + 10*time.Second)},
+			},
+			40 * time.Second,
+		},
+		// Test containers down at the same time count once
+		{
+			[]apiv1.ContainerStatus{
+				{RestartCount: 1, State: runningSince(time.Hour), LastTerminationState: terminatedAt(time.Hour + 30*time.Second)},
+				{RestartCount: 1, State: runningSince(time.Hour), LastTerminationState: terminatedAt(time.Hour + 10*time.Second)},
+			},
+			30 * time.Second,
+		},
+		// Test partially overlapping gaps are merged
+		{
+			[]apiv1.ContainerStatus{
+				{RestartCount: 1, State: runningSince(time.Hour), LastTerminationState: terminatedAt(time.Hour + 30*time.Second)},
+				{RestartCount: 1, State: runningSince(time.Hour - 20*time.Second), LastTerminationState: terminatedAt(time.Hour + 10*time.Second)},
+			},
+			50 * time.Second,
+		},
+		// Test a container that never restarted
+		{[]apiv1.ContainerStatus{{State: runningSince(time.Hour)}}, 0},
+	}
+
+	for i, test := range tests {
+		pod := apiv1.Pod{Status: apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: test.statuses}}
+		if downtime := EstimatedDowntime(&pod, now); downtime != test.expect {
+			t.Errorf("%d mismatch: got %v, expected %v", i, downtime, test.expect)
+		}
+	}
+}
+
+func TestHealthPoints(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-2 * time.Hour))
+	lastTransition := metav1.NewTime(now.Add(-time.Hour))
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect int
+	}{
+		// Test a ready pod without restarts
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test1", CreationTimestamp: created},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			100,
+		},
+		// Test a pod crash-looping for an hour
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test2", CreationTimestamp: created},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					Conditions: []apiv1.PodCondition{
+						{Type: apiv1.PodReady, Status: apiv1.ConditionFalse, LastTransitionTime: lastTransition},
+					},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount: 8,
+							State:        apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+							LastTerminationState: apiv1.ContainerState{
+								Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-time.Minute))},
+							},
+						},
+					},
+				},
+			},
+			0,
+		},
+		// Test a half-ready pod that restarted recently
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test3", CreationTimestamp: created},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+						{
+							RestartCount: 1,
+							State:        apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{
+								Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-time.Minute))},
+							},
+						},
+					},
+				},
+			},
+			69,
+		},
+		// Test a succeeded pod counts as ready
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test4", CreationTimestamp: created},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Succeeded",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed"}}},
+					},
+				},
+			},
+			100,
+		},
+	}
+
+	for i, test := range tests {
+		if got := HealthPoints(&test.pod, now); got != test.expect {
+			t.Errorf("%d mismatch: got %d, expected %d", i, got, test.expect)
+		}
+	}
+
+	readyOnly := HealthScoreOptions{ReadyWeight: 1}
+	if got := readyOnly.HealthPoints(&tests[2].pod, now); got != 50 {
+		t.Errorf("mismatch: got %d, expected 50 with readiness weighted alone", got)
+	}
+}
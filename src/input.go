@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// LoadPodsFromFile reads pods from a saved manifest for offline analysis. The
+// file may hold a single Pod, a PodList, a List as written by
+// "kubectl get -o yaml", or a stream of YAML documents separated by "---", in
+// YAML or JSON. Objects that are not pods are skipped.
+func LoadPodsFromFile(path string) (*apiv1.PodList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pods, err := decodePods(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading pods from %s: %w", path, err)
+	}
+	return pods, nil
+}
+
+func decodePods(r io.Reader) (*apiv1.PodList, error) {
+	pods := &apiv1.PodList{}
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return pods, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := appendPods(pods, doc); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func appendPods(pods *apiv1.PodList, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+	if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch obj := obj.(type) {
+	case *apiv1.Pod:
+		pods.Items = append(pods.Items, *obj)
+	case *apiv1.PodList:
+		pods.Items = append(pods.Items, obj.Items...)
+	case *apiv1.List:
+		for _, item := range obj.Items {
+			if err := appendPods(pods, item.Raw); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadPodsFromFile(t *testing.T) {
+	tests := []struct {
+		path   string
+		expect []string
+	}{
+		{"testdata/pods.yaml", []string{"web Running", "db-0 ContainerCreating"}},
+		{"testdata/podlist.json", []string{"api Completed"}},
+	}
+
+	for i, test := range tests {
+		pods, err := LoadPodsFromFile(test.path)
+		if err != nil {
+			t.Fatalf("%d unexpected error: %v", i, err)
+		}
+		var got []string
+		for j := range pods.Items {
+			got = append(got, pods.Items[j].Name+" "+printReason(&pods.Items[j]))
+		}
+		if !reflect.DeepEqual(test.expect, got) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, got))
+		}
+	}
+
+	if _, err := LoadPodsFromFile("testdata/missing.yaml"); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
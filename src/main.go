@@ -2,9 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +31,50 @@ func isPodInitializedConditionTrue(status *apiv1.PodStatus) bool {
 	return false
 }
 
+// excludeEphemeral drops the statuses of ephemeral debug containers. They are
+// not part of Spec.Containers, so like kubectl they count toward neither the
+// ready containers nor the restarts. The API reports them separately in
+// EphemeralContainerStatuses; this also guards against statuses merged from
+// both lists.
+func excludeEphemeral(pod *apiv1.Pod, statuses []apiv1.ContainerStatus) []apiv1.ContainerStatus {
+	if len(pod.Spec.EphemeralContainers) == 0 {
+		return statuses
+	}
+	ephemeral := make(map[string]bool, len(pod.Spec.EphemeralContainers))
+	for _, container := range pod.Spec.EphemeralContainers {
+		ephemeral[container.Name] = true
+	}
+	var kept []apiv1.ContainerStatus
+	for _, status := range statuses {
+		if !ephemeral[status.Name] {
+			kept = append(kept, status)
+		}
+	}
+	return kept
+}
+
+// isPodNodeLost reports whether the Ready condition was turned False because
+// the node stopped reporting.
+func isPodNodeLost(conditions []apiv1.PodCondition) bool {
+	for _, condition := range conditions {
+		if condition.Type == apiv1.PodReady && condition.Status != apiv1.ConditionTrue && condition.Reason == "NodeLost" {
+			return true
+		}
+	}
+	return false
+}
+
+func isRestartableInitContainer(initContainer *apiv1.Container) bool {
+	if initContainer == nil || initContainer.RestartPolicy == nil {
+		return false
+	}
+	return *initContainer.RestartPolicy == apiv1.ContainerRestartPolicyAlways
+}
+
+func isPodPhaseTerminal(phase apiv1.PodPhase) bool {
+	return phase == apiv1.PodFailed || phase == apiv1.PodSucceeded
+}
+
 func hasPodReadyCondition(conditions []apiv1.PodCondition) bool {
 	for _, condition := range conditions {
 		if condition.Type == apiv1.PodReady && condition.Status == apiv1.ConditionTrue {
@@ -41,6 +91,20 @@ func main() {
 	} else {
 		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
 	}
+	namespace := flag.String("namespace", metav1.NamespaceDefault, "namespace to list pods from, empty for all namespaces")
+	labelSelector := flag.String("l", "", "label selector to filter pods on, e.g. app=nginx")
+	showQOS := flag.Bool("show-qos", false, "add a QOS column to -o wide")
+	showHealth := flag.Bool("show-health", false, "add a HEALTH score column to -o wide")
+	restartingWithin := flag.Duration("restarting-within", 0, "mark pods with a container that terminated within this long as (restarting), e.g. 5m")
+	showSummary := flag.Bool("summary", false, "print pod counts by status after the table")
+	sortBy := flag.String("sort-by", "", "sort the table by name, status, age, restarts or ready")
+	sortDescending := flag.Bool("sort-desc", false, "reverse the order of -sort-by")
+	output := flag.String("o", "", "output format, one of: (empty), wide, json, yaml, go-template")
+	templateText := flag.String("template", "", "template for -o go-template, e.g. '{{range .}}{{.Name}} {{.Status}}\n{{end}}'")
+	outputVersion := flag.String("output-version", "", "schema version of the json and yaml output, defaults to the latest")
+	watchPods := flag.Bool("w", false, "watch pods and print a row on every change")
+	watchEvents := flag.Bool("watch-events", false, "watch pods and print a timestamped line per event instead of rows")
+	exitOnUnhealthy := flag.Bool("exit-on-unhealthy", false, "exit with status 1 when any listed pod is unhealthy")
 	flag.Parse()
 
 	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
@@ -53,47 +117,242 @@ func main() {
 		panic(err)
 	}
 	ctx := context.Background()
-	pods, err := clientset.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	if *watchPods || *watchEvents {
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+		watch := WatchPods
+		if *watchEvents {
+			watch = WatchPodEvents
+		}
+		if err := watch(ctx, clientset, *namespace, *labelSelector, os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	pods, err := FetchPods(ctx, clientset, *namespace, *labelSelector, metav1.ListOptions{})
 	if err != nil {
 		panic(err)
 	}
 
-	for _, pod := range pods.Items {
-		reason := printReason(&pod)
-		fmt.Printf("Pod: %s, Reason: %s\n", pod.Name, reason)
+	if *sortBy != "" {
+		if err := SortPodsDir(pods.Items, *sortBy, *sortDescending); err != nil {
+			panic(err)
+		}
 	}
+
+	f := DefaultFormatter
+	f.RestartingWithin = *restartingWithin
+	switch *output {
+	case "":
+		printTable := f.PrintPodTable
+		if *namespace == "" {
+			printTable = f.PrintPodTableAllNamespaces
+		}
+		if err := printTable(os.Stdout, pods, *showSummary); err != nil {
+			panic(err)
+		}
+	case "wide":
+		f.ShowQOS = *showQOS
+		f.ShowHealth = *showHealth
+		if err := f.PrintPodTableWide(os.Stdout, pods); err != nil {
+			panic(err)
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		for _, pod := range pods.Items {
+			summary, err := newPodSummary(&pod, *outputVersion, time.Now())
+			if err != nil {
+				panic(err)
+			}
+			if err := enc.Encode(summary); err != nil {
+				panic(err)
+			}
+		}
+	case "yaml":
+		for i := range pods.Items {
+			data, err := SummaryYAML(&pods.Items[i], *outputVersion, time.Now())
+			if err != nil {
+				panic(err)
+			}
+			fmt.Printf("---\n%s", data)
+		}
+	case "go-template":
+		var rows []PodRow
+		for i := range pods.Items {
+			rows = append(rows, BuildPodRow(&pods.Items[i]))
+		}
+		if err := RenderTemplate(os.Stdout, rows, *templateText); err != nil {
+			panic(err)
+		}
+	default:
+		panic(fmt.Sprintf("unknown output format %q", *output))
+	}
+
+	if *exitOnUnhealthy {
+		if healthy, unhealthy := AllHealthy(pods); !healthy {
+			fmt.Fprintf(os.Stderr, "unhealthy pods: %s\n", strings.Join(unhealthy, ", "))
+			os.Exit(1)
+		}
+	}
+}
+
+// podStatus holds the columns kubectl derives from a single pass over a pod's
+// container statuses.
+type podStatus struct {
+	reason       string
+	initializing bool
+	// initContainersDone counts the init containers completed before the
+	// first one still blocking initialization.
+	initContainersDone int
+	readyContainers    int
+	totalContainers    int
+	restarts           int
+	lastRestartDate    metav1.Time
+	// exitCode is the exit code of the terminated container that reason was
+	// taken from, if reason still is exitCodeReason.
+	exitCode       int32
+	exitCodeReason string
+}
+
+// terminatedReason renders a terminated container state like kubectl: its
+// Reason when the runtime set one, otherwise the signal that killed it,
+// otherwise its exit code, e.g. "ExitCode:0".
+func terminatedReason(terminated *apiv1.ContainerStateTerminated) string {
+	switch {
+	case terminated.Reason != "":
+		return terminated.Reason
+	case terminated.Signal != 0:
+		return fmt.Sprintf("Signal:%d", terminated.Signal)
+	default:
+		return fmt.Sprintf("ExitCode:%d", terminated.ExitCode)
+	}
+}
+
+// exitedCleanly reports whether a container terminated with exit code 0 and
+// no reason other than "Completed".
+func exitedCleanly(state apiv1.ContainerState) bool {
+	terminated := state.Terminated
+	return terminated != nil && terminated.ExitCode == 0 && terminated.Signal == 0 &&
+		(terminated.Reason == "" || terminated.Reason == "Completed")
 }
 
 func printReason(pod *apiv1.Pod) string {
+	return computePodStatus(pod).reason
+}
+
+// ReasonOptions tunes how PodStatusReasonWithOptions renders the reason. The
+// zero value renders like printReason.
+type ReasonOptions struct {
+	// ShowExitCode appends the non-zero exit code of the terminated container
+	// the reason comes from, e.g. "Error:1" or "OOMKilled:137".
+	ShowExitCode bool
+	// RestartingWithin appends " (restarting)" when a container terminated
+	// within that long, even if it is running again, see IsFlapping. 0
+	// disables the suffix. Formatter.RestartingWithin applies it to the STATUS
+	// column, set by the -restarting-within flag.
+	RestartingWithin time.Duration
+}
+
+func PodStatusReasonWithOptions(pod *apiv1.Pod, opts ReasonOptions) string {
+	return podStatusReasonWithOptions(pod, opts, time.Now())
+}
+
+func podStatusReasonWithOptions(pod *apiv1.Pod, opts ReasonOptions, now time.Time) string {
+	status := computePodStatus(pod)
+	reason := status.reason
+	if opts.ShowExitCode && status.exitCode != 0 && reason == status.exitCodeReason && !strings.Contains(reason, "ExitCode:") && !strings.Contains(reason, "Signal:") {
+		reason = fmt.Sprintf("%s:%d", reason, status.exitCode)
+	}
+	if opts.RestartingWithin > 0 && isFlapping(pod, opts.RestartingWithin, now) {
+		reason += " (restarting)"
+	}
+	return reason
+}
+
+func printReady(pod *apiv1.Pod) string {
+	status := computePodStatus(pod)
+	return fmt.Sprintf("%d/%d", status.readyContainers, status.totalContainers)
+}
+
+func printRestarts(pod *apiv1.Pod, now time.Time) string {
+	status := computePodStatus(pod)
+	if status.restarts != 0 && !status.lastRestartDate.IsZero() {
+		return fmt.Sprintf("%d (%s ago)", status.restarts, translateTimestampSince(status.lastRestartDate, now))
+	}
+	return strconv.Itoa(status.restarts)
+}
+
+func computePodStatus(pod *apiv1.Pod) podStatus {
+	restarts := 0
+	readyContainers := 0
+	lastRestartDate := metav1.NewTime(time.Time{})
+
 	reason := string(pod.Status.Phase)
 	if pod.Status.Reason != "" {
 		reason = pod.Status.Reason
 	}
 
 	// If the Pod carries {type:PodScheduled, reason:WaitingForGates}, set reason to 'SchedulingGated'.
+	// A gated pod has not been scheduled, so any container states it reports
+	// are stale and must not override the gate.
+	schedulingGated := false
 	for _, condition := range pod.Status.Conditions {
 		if condition.Type == apiv1.PodScheduled && condition.Reason == apiv1.PodReasonSchedulingGated {
 			reason = apiv1.PodReasonSchedulingGated
+			schedulingGated = true
 		}
 	}
 
+	totalContainers := len(pod.Spec.Containers)
+	initContainers := make(map[string]*apiv1.Container)
+	for i := range pod.Spec.InitContainers {
+		initContainers[pod.Spec.InitContainers[i].Name] = &pod.Spec.InitContainers[i]
+		if isRestartableInitContainer(&pod.Spec.InitContainers[i]) {
+			totalContainers++
+		}
+	}
+
+	restartableInitContainerRestarts := 0
+	lastRestartableInitContainerRestartDate := metav1.NewTime(time.Time{})
 	initializing := false
+	initContainersDone := 0
+	var exitCode int32
+	exitCodeReason := ""
 	for i := range pod.Status.InitContainerStatuses {
 		container := pod.Status.InitContainerStatuses[i]
+		restarts += int(container.RestartCount)
+		if container.LastTerminationState.Terminated != nil {
+			terminatedDate := container.LastTerminationState.Terminated.FinishedAt
+			if lastRestartDate.Before(&terminatedDate) {
+				lastRestartDate = terminatedDate
+			}
+		}
+		if isRestartableInitContainer(initContainers[container.Name]) {
+			restartableInitContainerRestarts += int(container.RestartCount)
+			if container.LastTerminationState.Terminated != nil {
+				terminatedDate := container.LastTerminationState.Terminated.FinishedAt
+				if lastRestartableInitContainerRestartDate.Before(&terminatedDate) {
+					lastRestartableInitContainerRestartDate = terminatedDate
+				}
+			}
+		}
 		switch {
 		case container.State.Terminated != nil && container.State.Terminated.ExitCode == 0:
+			initContainersDone++
+			continue
+		case isRestartableInitContainer(initContainers[container.Name]) &&
+			container.Started != nil && *container.Started:
+			// a started sidecar counts as done for initialization purposes
+			if container.Ready {
+				readyContainers++
+			}
+			initContainersDone++
 			continue
 		case container.State.Terminated != nil:
 			// initialization is failed
-			if len(container.State.Terminated.Reason) == 0 {
-				if container.State.Terminated.Signal != 0 {
-					reason = fmt.Sprintf("Init:Signal:%d", container.State.Terminated.Signal)
-				} else {
-					reason = fmt.Sprintf("Init:ExitCode:%d", container.State.Terminated.ExitCode)
-				}
-			} else {
-				reason = "Init:" + container.State.Terminated.Reason
-			}
+			reason = "Init:" + terminatedReason(container.State.Terminated)
+			exitCode, exitCodeReason = container.State.Terminated.ExitCode, reason
 			initializing = true
 		case container.State.Waiting != nil && len(container.State.Waiting.Reason) > 0 && container.State.Waiting.Reason != "PodInitializing":
 			reason = "Init:" + container.State.Waiting.Reason
@@ -105,23 +364,46 @@ func printReason(pod *apiv1.Pod) string {
 		break
 	}
 
-	if !initializing || isPodInitializedConditionTrue(&pod.Status) {
+	// Once initialization is done the main containers decide the reason, so a
+	// pod whose init and main containers have all exited reports the main
+	// containers' outcome, e.g. "Completed".
+	if initializing && isPodInitializedConditionTrue(&pod.Status) {
+		initializing = false
+	}
+	if !initializing {
+		restarts = restartableInitContainerRestarts
+		lastRestartDate = lastRestartableInitContainerRestartDate
 		hasRunning := false
-		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
-			container := pod.Status.ContainerStatuses[i]
+		containerStatuses := excludeEphemeral(pod, pod.Status.ContainerStatuses)
+		allExitedCleanly := len(containerStatuses) > 0
+		for i := len(containerStatuses) - 1; i >= 0; i-- {
+			container := containerStatuses[i]
+			restarts += int(container.RestartCount)
+			if container.LastTerminationState.Terminated != nil {
+				terminatedDate := container.LastTerminationState.Terminated.FinishedAt
+				if lastRestartDate.Before(&terminatedDate) {
+					lastRestartDate = terminatedDate
+				}
+			}
 			if container.State.Waiting != nil && container.State.Waiting.Reason != "" {
 				reason = container.State.Waiting.Reason
-			} else if container.State.Terminated != nil && container.State.Terminated.Reason != "" {
-				reason = container.State.Terminated.Reason
-			} else if container.State.Terminated != nil && container.State.Terminated.Reason == "" {
-				if container.State.Terminated.Signal != 0 {
-					reason = fmt.Sprintf("Signal:%d", container.State.Terminated.Signal)
-				} else {
-					reason = fmt.Sprintf("ExitCode:%d", container.State.Terminated.ExitCode)
+			} else if container.State.Terminated != nil {
+				// a bland "Error" must not hide another container that was OOMKilled
+				if terminated := terminatedReason(container.State.Terminated); terminated != "Error" || reason != "OOMKilled" {
+					reason = terminated
+					exitCode, exitCodeReason = container.State.Terminated.ExitCode, reason
 				}
 			} else if container.Ready && container.State.Running != nil {
 				hasRunning = true
+				readyContainers++
 			}
+			allExitedCleanly = allExitedCleanly && exitedCleanly(container.State)
+		}
+
+		// every container finishing successfully reads Completed even where
+		// the runtime left the reason empty, e.g. "ExitCode:0"
+		if allExitedCleanly {
+			reason = "Completed"
 		}
 
 		// change pod status back to "Running" if there is at least one container still reporting as "Running" status
@@ -134,11 +416,45 @@ func printReason(pod *apiv1.Pod) string {
 		}
 	}
 
+	if schedulingGated {
+		reason = apiv1.PodReasonSchedulingGated
+	}
+
+	// the phase of a pod on a lost node is stale, but a container reason is
+	// still the better explanation
+	if (reason == string(apiv1.PodRunning) || reason == string(apiv1.PodUnknown)) && isPodNodeLost(pod.Status.Conditions) {
+		reason = "NodeLost"
+	}
+
+	// a finished pod reads Completed whatever its containers last reported.
+	// A failed one reads the reason the kubelet failed it for, e.g. Evicted,
+	// then its containers' reason, and Error when neither is more specific.
+	switch pod.Status.Phase {
+	case apiv1.PodSucceeded:
+		reason = "Completed"
+	case apiv1.PodFailed:
+		if pod.Status.Reason != "" {
+			reason = pod.Status.Reason
+		} else if reason == string(apiv1.PodFailed) {
+			reason = "Error"
+		}
+	}
+
 	if pod.DeletionTimestamp != nil && pod.Status.Reason == node.NodeUnreachablePodReason {
 		reason = "Unknown"
-	} else if pod.DeletionTimestamp != nil {
+	} else if pod.DeletionTimestamp != nil && !isPodPhaseTerminal(pod.Status.Phase) {
 		reason = "Terminating"
 	}
 
-	return reason
+	return podStatus{
+		reason:             reason,
+		initializing:       initializing,
+		initContainersDone: initContainersDone,
+		readyContainers:    readyContainers,
+		totalContainers:    totalContainers,
+		restarts:           restarts,
+		lastRestartDate:    lastRestartDate,
+		exitCode:           exitCode,
+		exitCodeReason:     exitCodeReason,
+	}
 }
@@ -10,6 +10,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var (
+	trueVal                      = true
+	falseVal                     = false
+	containerRestartPolicyAlways = apiv1.ContainerRestartPolicyAlways
+)
+
 func TestPrintReason(t *testing.T) {
 	tests := []struct {
 		pod    apiv1.Pod
@@ -354,6 +360,514 @@ func TestPrintReason(t *testing.T) {
 			},
 			apiv1.PodReasonSchedulingGated,
 		},
+		{
+			// Test init container in CrashLoopBackOff
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test16"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 1), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Pending",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{RestartCount: 3, State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+					},
+				},
+			},
+			"Init:CrashLoopBackOff",
+		},
+		{
+			// Test init container terminated with a non-zero exit code and no reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test17"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 1), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Pending",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 137}}},
+					},
+				},
+			},
+			"Init:ExitCode:137",
+		},
+		{
+			// Test init container terminated by a signal and no reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test18"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 1), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Pending",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 137, Signal: 9}}},
+					},
+				},
+			},
+			"Init:Signal:9",
+		},
+		{
+			// Test init container terminated with a reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test19"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 2), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Pending",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 0}}},
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+					},
+				},
+			},
+			"Init:Error",
+		},
+		{
+			// Test evicted pod reports the pod-level reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test20"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:   apiv1.PodFailed,
+					Reason:  "Evicted",
+					Message: "The node was low on resource: memory.",
+				},
+			},
+			"Evicted",
+		},
+		{
+			// Test single container terminated with OOMKilled
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test21"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{RestartCount: 1, State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+					},
+				},
+			},
+			"OOMKilled",
+		},
+		{
+			// Test OOMKilled wins over a bland Error from the first container
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test22"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+					},
+				},
+			},
+			"OOMKilled",
+		},
+		{
+			// Test the first container still wins over other terminated reasons
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test23"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}},
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed", ExitCode: 0}}},
+					},
+				},
+			},
+			"Error",
+		},
+		{
+			// Test container killed by SIGKILL without a reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test24"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Signal: 9, ExitCode: 137}}},
+					},
+				},
+			},
+			"Signal:9",
+		},
+		{
+			// Test container exiting with code 2 without a reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test25"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 2}}},
+					},
+				},
+			},
+			"ExitCode:2",
+		},
+		{
+			// Test pod with every container terminated successfully
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test26"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed", ExitCode: 0}}},
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 0}}},
+					},
+				},
+			},
+			"Completed",
+		},
+		{
+			// Test Succeeded pod without container statuses
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test27"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodSucceeded,
+				},
+			},
+			"Completed",
+		},
+		{
+			// Test pod with init and main containers all done, main containers win
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test28"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 1), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodSucceeded,
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed", ExitCode: 0}}},
+					},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed", ExitCode: 0}}},
+					},
+				},
+			},
+			"Completed",
+		},
+		{
+			// Test started sidecar init container counts as complete while the next init container waits
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test29"},
+				Spec: apiv1.PodSpec{
+					InitContainers: []apiv1.Container{
+						{Name: "sidecar", RestartPolicy: &containerRestartPolicyAlways},
+						{Name: "setup"},
+					},
+					Containers: make([]apiv1.Container, 1),
+				},
+				Status: apiv1.PodStatus{
+					Phase: "Pending",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "sidecar", Started: &trueVal, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+						{Name: "setup", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+					},
+				},
+			},
+			"Init:1/2",
+		},
+		{
+			// Test sidecar init container not yet started still blocks initialization
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test30"},
+				Spec: apiv1.PodSpec{
+					InitContainers: []apiv1.Container{
+						{Name: "sidecar", RestartPolicy: &containerRestartPolicyAlways},
+						{Name: "setup"},
+					},
+					Containers: make([]apiv1.Container, 1),
+				},
+				Status: apiv1.PodStatus{
+					Phase: "Pending",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "sidecar", Started: &falseVal, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+						{Name: "setup", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+					},
+				},
+			},
+			"Init:0/2",
+		},
+		{
+			// Test Succeeded pod reads Completed even if a container reports an odd reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test31"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodSucceeded,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "ContainerStatusUnknown", ExitCode: 0}}},
+					},
+				},
+			},
+			"Completed",
+		},
+		{
+			// Test Succeeded pod being deleted still reads Completed
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test32", DeletionTimestamp: &metav1.Time{Time: time.Now()}},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodSucceeded,
+				},
+			},
+			"Completed",
+		},
+		{
+			// Test Failed pod without any more specific reason reads Error
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test33"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodFailed,
+				},
+			},
+			"Error",
+		},
+		{
+			// Test Failed pod being deleted keeps its container reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test34", DeletionTimestamp: &metav1.Time{Time: time.Now()}},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodFailed,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+					},
+				},
+			},
+			"OOMKilled",
+		},
+		{
+			// Test running pod being deleted reads Terminating
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test35", DeletionTimestamp: &metav1.Time{Time: time.Now()}},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodRunning,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			"Terminating",
+		},
+		{
+			// Test init containers still being created read as init progress
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test36"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 2), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodPending,
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+						{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+					},
+				},
+			},
+			"Init:0/2",
+		},
+		{
+			// Test main container being created after init completed
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test37"},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 1), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodPending,
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed"}}},
+					},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+					},
+				},
+			},
+			"ContainerCreating",
+		},
+		{
+			// Test running sidecar and completed init container hand over to the main container
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test38"},
+				Spec: apiv1.PodSpec{
+					InitContainers: []apiv1.Container{
+						{Name: "sidecar", RestartPolicy: &containerRestartPolicyAlways},
+						{Name: "setup"},
+					},
+					Containers: []apiv1.Container{{Name: "app"}},
+				},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "sidecar", Ready: true, Started: &trueVal, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+						{Name: "setup", State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed"}}},
+					},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "app", Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			"Running",
+		},
+		{
+			// Test container killed by SIGTERM next to a running container
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test39"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Signal: 15, ExitCode: 143}}},
+					},
+				},
+			},
+			"Signal:15",
+		},
+		{
+			// Test failed pod whose container only reports an exit code
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test40"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodFailed,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 3}}},
+					},
+				},
+			},
+			"ExitCode:3",
+		},
+		{
+			// Test pod in the Unknown phase
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test41"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodUnknown,
+				},
+			},
+			"Unknown",
+		},
+		{
+			// Test pod on a lost node with a stale running container
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test42"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:      apiv1.PodRunning,
+					Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionFalse, Reason: "NodeLost"}},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			"NodeLost",
+		},
+		{
+			// Test pod in the Unknown phase on a lost node
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test43"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:      apiv1.PodUnknown,
+					Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionUnknown, Reason: "NodeLost"}},
+				},
+			},
+			"NodeLost",
+		},
+		{
+			// Test crash-looping container on a lost node keeps its reason
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test44"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:      apiv1.PodRunning,
+					Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionFalse, Reason: "NodeLost"}},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{RestartCount: 4, State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+					},
+				},
+			},
+			"CrashLoopBackOff",
+		},
+		{
+			// Test evicted pod keeps Evicted over its containers' reasons
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test45"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:   apiv1.PodFailed,
+					Reason:  "Evicted",
+					Message: "The node was low on resource: ephemeral-storage.",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "ContainerStatusUnknown", ExitCode: 137}}},
+					},
+				},
+			},
+			"Evicted",
+		},
+		{
+			// Test pod rejected by the kubelet for lack of cpu
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test46"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:   apiv1.PodFailed,
+					Reason:  "OutOfcpu",
+					Message: "Pod Node didn't have enough resource: cpu, requested: 4000, used: 1000, capacity: 2000",
+				},
+			},
+			"OutOfcpu",
+		},
+		{
+			// Test failed pod without a reason falls back to its container
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test47"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: apiv1.PodFailed,
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+					},
+				},
+			},
+			"OOMKilled",
+		},
+		{
+			// Test SchedulingGated wins over a bogus waiting container
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test48"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Pending",
+					Conditions: []apiv1.PodCondition{
+						{
+							Type:   apiv1.PodScheduled,
+							Status: apiv1.ConditionFalse,
+							Reason: apiv1.PodReasonSchedulingGated,
+						},
+					},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+					},
+				},
+			},
+			apiv1.PodReasonSchedulingGated,
+		},
+		{
+			// Test clean exit without a reason next to a running container reads like kubectl
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test49"},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 0}}},
+						{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			"ExitCode:0",
+		},
 	}
 
 	for i, test := range tests {
@@ -363,3 +877,102 @@ func TestPrintReason(t *testing.T) {
 		}
 	}
 }
+
+func TestTerminatedReason(t *testing.T) {
+	tests := []struct {
+		terminated apiv1.ContainerStateTerminated
+		expect     string
+	}{
+		{apiv1.ContainerStateTerminated{Reason: "OOMKilled", Signal: 9, ExitCode: 137}, "OOMKilled"},
+		{apiv1.ContainerStateTerminated{Signal: 9, ExitCode: 137}, "Signal:9"},
+		{apiv1.ContainerStateTerminated{ExitCode: 1}, "ExitCode:1"},
+		{apiv1.ContainerStateTerminated{}, "ExitCode:0"},
+	}
+
+	for i, test := range tests {
+		reason := terminatedReason(&test.terminated)
+		if !reflect.DeepEqual(test.expect, reason) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, reason))
+		}
+	}
+}
+
+func TestPodStatusReasonWithOptions(t *testing.T) {
+	terminated := func(phase apiv1.PodPhase, state apiv1.ContainerStateTerminated) apiv1.Pod {
+		return apiv1.Pod{
+			Spec: apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             phase,
+				ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Terminated: &state}}},
+			},
+		}
+	}
+	oomKilled := terminated(apiv1.PodRunning, apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137})
+	errored := terminated(apiv1.PodFailed, apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1})
+	signaled := terminated(apiv1.PodRunning, apiv1.ContainerStateTerminated{Signal: 9, ExitCode: 137})
+	succeeded := terminated(apiv1.PodSucceeded, apiv1.ContainerStateTerminated{Reason: "Completed"})
+
+	tests := []struct {
+		pod    apiv1.Pod
+		opts   ReasonOptions
+		expect string
+	}{
+		{oomKilled, ReasonOptions{}, "OOMKilled"},
+		{oomKilled, ReasonOptions{ShowExitCode: true}, "OOMKilled:137"},
+		{errored, ReasonOptions{}, "Error"},
+		{errored, ReasonOptions{ShowExitCode: true}, "Error:1"},
+		{signaled, ReasonOptions{ShowExitCode: true}, "Signal:9"},
+		{succeeded, ReasonOptions{ShowExitCode: true}, "Completed"},
+	}
+
+	for i, test := range tests {
+		reason := PodStatusReasonWithOptions(&test.pod, test.opts)
+		if !reflect.DeepEqual(test.expect, reason) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, reason))
+		}
+		if !test.opts.ShowExitCode && reason != printReason(&test.pod) {
+			t.Errorf("%d default options render %q, printReason renders %q", i, reason, printReason(&test.pod))
+		}
+	}
+}
+
+func TestEphemeralContainersExcluded(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	debugStatus := apiv1.ContainerStatus{
+		Name:                 "debugger",
+		RestartCount:         2,
+		State:                apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+		LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+	}
+	appStatus := apiv1.ContainerStatus{Name: "app", Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}
+	pod := func(containerStatuses []apiv1.ContainerStatus) apiv1.Pod {
+		return apiv1.Pod{
+			Spec: apiv1.PodSpec{
+				Containers:          []apiv1.Container{{Name: "app"}},
+				EphemeralContainers: []apiv1.EphemeralContainer{{EphemeralContainerCommon: apiv1.EphemeralContainerCommon{Name: "debugger"}}},
+			},
+			Status: apiv1.PodStatus{
+				Phase:                      apiv1.PodRunning,
+				Conditions:                 []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionTrue}},
+				ContainerStatuses:          containerStatuses,
+				EphemeralContainerStatuses: []apiv1.ContainerStatus{debugStatus},
+			},
+		}
+	}
+
+	tests := []struct {
+		pod apiv1.Pod
+	}{
+		// Test debug container reported separately
+		{pod([]apiv1.ContainerStatus{appStatus})},
+		// Test debug container status merged into the container statuses
+		{pod([]apiv1.ContainerStatus{appStatus, debugStatus})},
+	}
+
+	for i, test := range tests {
+		ready, reason, restarts := printReady(&test.pod), printReason(&test.pod), printRestarts(&test.pod, now)
+		if ready != "1/1" || reason != "Running" || restarts != "0" {
+			t.Errorf("%d mismatch: got (%s, %s, %s), expected (1/1, Running, 0)", i, ready, reason, restarts)
+		}
+	}
+}
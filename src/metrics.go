@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+var labelValueReplacer = strings.NewReplacer("(", "", ")", "", " ", "_")
+
+// normalizeLabelValue lowercases value and drops the characters that are
+// awkward in a metric label, e.g. "3 (60s ago)" becomes "3_60s_ago".
+func normalizeLabelValue(value string) string {
+	return labelValueReplacer.Replace(strings.ToLower(value))
+}
+
+// StatusLabels returns the columns operators see in the table as metric label
+// values: reason, phase, severity, ready, restarts and node. The node of an
+// unscheduled pod is "none".
+func StatusLabels(pod *apiv1.Pod, now time.Time) map[string]string {
+	reason := printReason(pod)
+	node := pod.Spec.NodeName
+	if node == "" {
+		node = "none"
+	}
+	return map[string]string{
+		"reason":   normalizeLabelValue(reason),
+		"phase":    normalizeLabelValue(string(pod.Status.Phase)),
+		"severity": normalizeLabelValue(ClassifyReason(reason).String()),
+		"ready":    normalizeLabelValue(printReady(pod)),
+		"restarts": normalizeLabelValue(printRestarts(pod, now)),
+		"node":     normalizeLabelValue(node),
+	}
+}
+
+var (
+	podStatusCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pod_status_count",
+		Help: "Number of pods by the reason shown in the STATUS column.",
+	}, []string{"reason"})
+
+	// metricsMu guards podStatusReasons, the reasons UpdateMetrics has
+	// exported so far.
+	metricsMu        sync.Mutex
+	podStatusReasons = map[string]bool{}
+)
+
+// RegisterMetrics registers the pod_status_count gauge with reg.
+func RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(podStatusCount)
+}
+
+// UpdateMetrics sets pod_status_count from the SummarizeStatuses counts of
+// pods. Reasons exported by an earlier update that no pod shows anymore are
+// set to 0 rather than left stale.
+func UpdateMetrics(pods *apiv1.PodList) {
+	counts := map[string]int{}
+	for reason, count := range SummarizeStatuses(pods) {
+		counts[normalizeLabelValue(reason)] += count
+	}
+
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	for reason := range podStatusReasons {
+		if _, ok := counts[reason]; !ok {
+			podStatusCount.WithLabelValues(reason).Set(0)
+		}
+	}
+	for reason, count := range counts {
+		podStatusCount.WithLabelValues(reason).Set(float64(count))
+		podStatusReasons[reason] = true
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatusLabels(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       apiv1.PodSpec{NodeName: "Node-1", Containers: make([]apiv1.Container, 2)},
+		Status: apiv1.PodStatus{
+			Phase: apiv1.PodRunning,
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+				{
+					RestartCount:         7,
+					State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+				},
+			},
+		},
+	}
+
+	expect := map[string]string{
+		"reason":   "crashloopbackoff",
+		"phase":    "running",
+		"severity": "error",
+		"ready":    "1/2",
+		"restarts": "7_60s_ago",
+		"node":     "node-1",
+	}
+	labels := StatusLabels(&pod, now)
+	if !reflect.DeepEqual(expect, labels) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, labels))
+	}
+}
+
+func TestUpdateMetrics(t *testing.T) {
+	running := containerPod(apiv1.PodRunning, runningState)
+	crashing := containerPod(apiv1.PodRunning, crashLoopState)
+	pending := pendingPod
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := RegisterMetrics(reg); err != nil {
+		t.Fatal(err)
+	}
+
+	UpdateMetrics(&apiv1.PodList{Items: []apiv1.Pod{running, running, crashing, pending}})
+	expect := `
+# HELP pod_status_count Number of pods by the reason shown in the STATUS column.
+# TYPE pod_status_count gauge
+pod_status_count{reason="crashloopbackoff"} 1
+pod_status_count{reason="pending"} 1
+pod_status_count{reason="running"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expect), "pod_status_count"); err != nil {
+		t.Error(err)
+	}
+
+	UpdateMetrics(&apiv1.PodList{Items: []apiv1.Pod{running, running, running}})
+	expect = `
+# HELP pod_status_count Number of pods by the reason shown in the STATUS column.
+# TYPE pod_status_count gauge
+pod_status_count{reason="crashloopbackoff"} 0
+pod_status_count{reason="pending"} 0
+pod_status_count{reason="running"} 3
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expect), "pod_status_count"); err != nil {
+		t.Error(err)
+	}
+}
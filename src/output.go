@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// PodRow is the computed status of a pod in a machine-friendly shape.
+type PodRow struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Ready     string        `json:"ready"`
+	Status    string        `json:"status"`
+	Restarts  string        `json:"restarts"`
+	Age       time.Duration `json:"age"`
+}
+
+// MarshalJSON encodes Age as whole seconds so consumers get a number.
+func (r PodRow) MarshalJSON() ([]byte, error) {
+	type podRow PodRow
+	return json.Marshal(struct {
+		podRow
+		Age int64 `json:"age"`
+	}{
+		podRow: podRow(r),
+		Age:    int64(r.Age / time.Second),
+	})
+}
+
+func BuildPodRow(pod *apiv1.Pod) PodRow {
+	return buildPodRow(pod, time.Now())
+}
+
+func buildPodRow(pod *apiv1.Pod, now time.Time) PodRow {
+	var age time.Duration
+	if !pod.CreationTimestamp.IsZero() {
+		age = now.Sub(pod.CreationTimestamp.Time)
+	}
+	return PodRow{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Ready:     printReady(pod),
+		Status:    printReason(pod),
+		Restarts:  printRestarts(pod, now),
+		Age:       age,
+	}
+}
+
+// PodRowWide is a PodRow with the placement columns of "-o wide", rendered
+// exactly like the cells of the Formatter's wide table, see PodTableRowWide.
+type PodRowWide struct {
+	PodRow
+	Node           string `json:"node"`
+	IP             string `json:"ip"`
+	NominatedNode  string `json:"nominatedNode"`
+	ReadinessGates string `json:"readinessGates"`
+	RestartReason  string `json:"restartReason"`
+}
+
+// MarshalJSON encodes Age as whole seconds like PodRow does.
+func (r PodRowWide) MarshalJSON() ([]byte, error) {
+	type podRow PodRow
+	return json.Marshal(struct {
+		podRow
+		Age            int64  `json:"age"`
+		Node           string `json:"node"`
+		IP             string `json:"ip"`
+		NominatedNode  string `json:"nominatedNode"`
+		ReadinessGates string `json:"readinessGates"`
+		RestartReason  string `json:"restartReason"`
+	}{
+		podRow:         podRow(r.PodRow),
+		Age:            int64(r.Age / time.Second),
+		Node:           r.Node,
+		IP:             r.IP,
+		NominatedNode:  r.NominatedNode,
+		ReadinessGates: r.ReadinessGates,
+		RestartReason:  r.RestartReason,
+	})
+}
+
+func BuildPodRowWide(pod *apiv1.Pod) PodRowWide {
+	return DefaultFormatter.buildPodRowWide(pod, time.Now())
+}
+
+func (f Formatter) buildPodRowWide(pod *apiv1.Pod, now time.Time) PodRowWide {
+	row := f.NewPodTableRowWide(pod, now)
+	return PodRowWide{
+		PodRow:         buildPodRow(pod, now),
+		Node:           row.Node,
+		IP:             row.IP,
+		NominatedNode:  row.NominatedNode,
+		ReadinessGates: row.ReadinessGates,
+		RestartReason:  row.RestartReason,
+	}
+}
+
+// PrintPodTableWide writes pods to w like "kubectl get pods -o wide".
+func PrintPodTableWide(w io.Writer, pods *apiv1.PodList) error {
+	return DefaultFormatter.PrintPodTableWide(w, pods)
+}
+
+// PrintPodTableWide writes pods to w like "kubectl get pods -o wide" plus a
+// RESTART-REASON column, with trailing QOS and HEALTH columns when ShowQOS and ShowHealth are set.
+func (f Formatter) PrintPodTableWide(w io.Writer, pods *apiv1.PodList) error {
+	return f.printPodTableWide(w, pods, time.Now())
+}
+
+func (f Formatter) printPodTableWide(w io.Writer, pods *apiv1.PodList, now time.Time) error {
+	tw := newTabWriter(w)
+	header := "NAME\tREADY\tSTATUS\tRESTARTS\tAGE\tIP\tNODE\tNOMINATED NODE\tREADINESS GATES\tRESTART-REASON"
+	if f.ShowQOS {
+		header += "\tQOS"
+	}
+	if f.ShowHealth {
+		header += "\tHEALTH"
+	}
+	if _, err := fmt.Fprintln(tw, header); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		row := f.NewPodTableRowWide(pod, now)
+		line := strings.Join([]string{row.Name, row.Ready, row.Status, row.Restarts, row.Age, row.IP, row.Node, row.NominatedNode, row.ReadinessGates, row.RestartReason}, "\t")
+		if f.ShowQOS {
+			line += "\t" + string(ComputeQOS(pod))
+		}
+		if f.ShowHealth {
+			line += "\t" + strconv.Itoa(HealthPoints(pod, now))
+		}
+		if _, err := fmt.Fprintln(tw, line); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// WritePodDetail writes the row of pod followed by one indented line per init,
+// regular and ephemeral container status, e.g.
+// "  └ app: Terminated(OOMKilled, exit 137) restarts=3". Terminated reasons are
+// synthesized from the signal or exit code like the STATUS column.
+func WritePodDetail(w io.Writer, pod *apiv1.Pod) error {
+	return writePodDetail(w, pod, time.Now())
+}
+
+func writePodDetail(w io.Writer, pod *apiv1.Pod, now time.Time) error {
+	row := buildPodRow(pod, now)
+	tw := newTabWriter(w)
+	if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", row.Name, row.Ready, row.Status, row.Restarts, HumanDuration(row.Age)); err != nil {
+		return err
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses} {
+		for _, container := range statuses {
+			if _, err := fmt.Fprintf(w, "  └ %s: %s restarts=%d\n", container.Name, printContainerState(container.State), container.RestartCount); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// printContainerState renders state as Running, Waiting(reason) or
+// Terminated(reason, exit N).
+func printContainerState(state apiv1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil && state.Waiting.Reason != "":
+		return fmt.Sprintf("Waiting(%s)", state.Waiting.Reason)
+	case state.Waiting != nil:
+		return "Waiting"
+	case state.Terminated != nil:
+		return fmt.Sprintf("Terminated(%s, exit %d)", terminatedReason(state.Terminated), state.Terminated.ExitCode)
+	default:
+		return "Unknown"
+	}
+}
+
+// WritePodRowsJSON writes rows as a JSON array.
+func WritePodRowsJSON(w io.Writer, rows []PodRow) error {
+	if rows == nil {
+		rows = []PodRow{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+// WritePodRowsCSV writes rows as CSV with a NAME,READY,STATUS,RESTARTS,AGE
+// header, the age rendered like the AGE column.
+func WritePodRowsCSV(w io.Writer, rows []PodRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"NAME", "READY", "STATUS", "RESTARTS", "AGE"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write([]string{row.Name, row.Ready, row.Status, row.Restarts, HumanDuration(row.Age)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WritePodCSV writes pods as CSV with a
+// NAME,NAMESPACE,READY,STATUS,RESTARTS,AGE,NODE header, the columns rendered
+// like the table. The node of an unscheduled pod is left empty.
+func WritePodCSV(w io.Writer, pods []apiv1.Pod, now time.Time) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"NAME", "NAMESPACE", "READY", "STATUS", "RESTARTS", "AGE", "NODE"}); err != nil {
+		return err
+	}
+	for i := range pods {
+		row := buildPodRow(&pods[i], now)
+		if err := cw.Write([]string{row.Name, row.Namespace, row.Ready, row.Status, row.Restarts, PodAge(&pods[i], now), pods[i].Spec.NodeName}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// RenderTemplate executes the text/template tmpl over rows, like
+// "kubectl -o go-template", e.g. "{{range .}}{{.Name}} {{.Status}}\n{{end}}".
+func RenderTemplate(w io.Writer, rows []PodRow, tmpl string) error {
+	t, err := template.New("pods").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+	if err := t.Execute(w, rows); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	return nil
+}
+
+// RenderOrgTable writes pods as an Emacs Org-mode table with a header row and
+// a "|---+---|" separator. Pipes inside cells are escaped as "\vert{}".
+func RenderOrgTable(w io.Writer, pods []apiv1.Pod, now time.Time) error {
+	rows := [][]string{{"NAME", "READY", "STATUS", "RESTARTS", "AGE"}}
+	for i := range pods {
+		row := NewPodTableRow(&pods[i], now)
+		rows = append(rows, []string{row.Name, row.Ready, row.Status, row.Restarts, row.Age})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for j, cell := range row {
+			row[j] = strings.ReplaceAll(cell, "|", "\\vert{}")
+			if n := utf8.RuneCountInString(row[j]); n > widths[j] {
+				widths[j] = n
+			}
+		}
+	}
+
+	for i, row := range rows {
+		if _, err := fmt.Fprintln(w, orgRow(row, widths)); err != nil {
+			return err
+		}
+		if i == 0 {
+			if _, err := fmt.Fprintln(w, orgSeparator(widths)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func orgRow(cells []string, widths []int) string {
+	var b strings.Builder
+	b.WriteString("|")
+	for i, cell := range cells {
+		b.WriteString(" " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " |")
+	}
+	return b.String()
+}
+
+func orgSeparator(widths []int) string {
+	cells := make([]string, len(widths))
+	for i, width := range widths {
+		cells[i] = strings.Repeat("-", width+2)
+	}
+	return "|" + strings.Join(cells, "+") + "|"
+}
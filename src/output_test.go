@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWritePodRowsJSON(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pods := []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             "Running",
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "prod", CreationTimestamp: metav1.NewTime(now.Add(-45 * time.Second))},
+			Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 2), Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase: "Pending",
+				InitContainerStatuses: []apiv1.ContainerStatus{
+					{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{}}},
+					{State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+				},
+			},
+		},
+	}
+
+	var rows []PodRow
+	for i := range pods {
+		rows = append(rows, buildPodRow(&pods[i], now))
+	}
+	var buf bytes.Buffer
+	if err := WritePodRowsJSON(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	expect := []map[string]interface{}{
+		{"name": "web", "namespace": "prod", "ready": "1/1", "status": "Running", "restarts": "0", "age": float64(7200)},
+		{"name": "db", "namespace": "prod", "ready": "0/1", "status": "Init:1/2", "restarts": "0", "age": float64(45)},
+	}
+	if !reflect.DeepEqual(expect, decoded) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, decoded))
+	}
+}
+
+func TestRenderOrgTable(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pods := []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             "Running",
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "odd|name", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: "Pending"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderOrgTable(&buf, pods, now); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"| NAME           | READY | STATUS  | RESTARTS | AGE |\n" +
+		"|----------------+-------+---------+----------+-----|\n" +
+		"| web            | 1/1   | Running | 0        | 60m |\n" +
+		"| odd\\vert{}name | 0/1   | Pending | 0        | 60s |\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	rows := []PodRow{
+		{Name: "web", Namespace: "prod", Ready: "1/1", Status: "Running", Restarts: "0", Age: time.Hour},
+		{Name: "api", Namespace: "prod", Ready: "0/1", Status: "CrashLoopBackOff", Restarts: "4", Age: time.Minute},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTemplate(&buf, rows, "{{range .}}{{.Namespace}}/{{.Name}} {{.Status}} {{.Age}}\n{{end}}"); err != nil {
+		t.Fatal(err)
+	}
+	expect := "prod/web Running 1h0m0s\nprod/api CrashLoopBackOff 1m0s\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+
+	for i, tmpl := range []string{"{{range .}}{{.Name}", "{{range .}}{{.Node}}{{end}}"} {
+		if err := RenderTemplate(&bytes.Buffer{}, rows, tmpl); err == nil {
+			t.Errorf("%d expected an error for template %q", i, tmpl)
+		}
+	}
+}
+
+func TestWritePodRowsCSV(t *testing.T) {
+	rows := []PodRow{
+		{Name: "web", Namespace: "prod", Ready: "1/1", Status: "Running", Restarts: "2 (5m ago)", Age: 3 * 24 * time.Hour},
+		{Name: "api", Namespace: "prod", Ready: "0/1", Status: "Init:a,b", Restarts: "0", Age: 90 * time.Second},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePodRowsCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"NAME,READY,STATUS,RESTARTS,AGE\n" +
+		"web,1/1,Running,2 (5m ago),3d\n" +
+		"api,0/1,\"Init:a,b\",0,90s\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
+
+func TestWritePodCSV(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pods := []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", CreationTimestamp: metav1.NewTime(now.Add(-3 * 24 * time.Hour))},
+			Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             apiv1.PodRunning,
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch,nightly", Namespace: "team \"a\"", CreationTimestamp: metav1.NewTime(now.Add(-90 * time.Second))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePodCSV(&buf, pods, now); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"NAME,NAMESPACE,READY,STATUS,RESTARTS,AGE,NODE\n" +
+		"web,prod,1/1,Running,0,3d,node-1\n" +
+		"\"batch,nightly\",\"team \"\"a\"\"\",0/1,Pending,0,90s,\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
+
+func TestBuildPodRowWide(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect PodRowWide
+	}{
+		{
+			// Test pending pod nominated to a node by preemption
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+				Spec: apiv1.PodSpec{
+					Containers:     make([]apiv1.Container, 1),
+					ReadinessGates: []apiv1.PodReadinessGate{{ConditionType: "example.com/lb"}, {ConditionType: "example.com/dns"}},
+				},
+				Status: apiv1.PodStatus{Phase: "Pending", NominatedNodeName: "node-2"},
+			},
+			PodRowWide{
+				PodRow:         PodRow{Name: "web", Namespace: "prod", Ready: "0/1", Status: "Pending", Restarts: "0", Age: time.Minute},
+				Node:           "<none>",
+				IP:             "<none>",
+				NominatedNode:  "node-2",
+				ReadinessGates: "0/2",
+				RestartReason:  "<none>",
+			},
+		},
+		{
+			// Test scheduled pod without a nominated node
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:             "Running",
+					PodIP:             "10.0.0.9",
+					ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+				},
+			},
+			PodRowWide{
+				PodRow:         PodRow{Name: "api", Namespace: "prod", Ready: "1/1", Status: "Running", Restarts: "0", Age: time.Hour},
+				Node:           "node-1",
+				IP:             "10.0.0.9",
+				NominatedNode:  "<none>",
+				ReadinessGates: "<none>",
+				RestartReason:  "<none>",
+			},
+		},
+	}
+
+	var pods apiv1.PodList
+	for i, test := range tests {
+		row := DefaultFormatter.buildPodRowWide(&test.pod, now)
+		if !reflect.DeepEqual(test.expect, row) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, row))
+		}
+		table := DefaultFormatter.NewPodTableRowWide(&test.pod, now)
+		if cells := [...]string{row.Node, row.IP, row.NominatedNode, row.ReadinessGates, row.RestartReason}; cells != [...]string{table.Node, table.IP, table.NominatedNode, table.ReadinessGates, table.RestartReason} {
+			t.Errorf("%d mismatch: got %q, expected the cells of the wide table %+v", i, cells, table)
+		}
+		pods.Items = append(pods.Items, test.pod)
+	}
+
+	encoded, err := json.Marshal(DefaultFormatter.buildPodRowWide(&tests[1].pod, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectJSON := `{"name":"api","namespace":"prod","ready":"1/1","status":"Running","restarts":"0","age":3600,"node":"node-1","ip":"10.0.0.9","nominatedNode":"\u003cnone\u003e","readinessGates":"\u003cnone\u003e","restartReason":"\u003cnone\u003e"}`
+	if string(encoded) != expectJSON {
+		t.Errorf("mismatch: %s", cmp.Diff(expectJSON, string(encoded)))
+	}
+
+	var buf bytes.Buffer
+	if err := DefaultFormatter.printPodTableWide(&buf, &pods, now); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"NAME   READY   STATUS    RESTARTS   AGE   IP         NODE     NOMINATED NODE   READINESS GATES   RESTART-REASON\n" +
+		"web    0/1     Pending   0          60s   <none>     <none>   node-2           0/2               <none>\n" +
+		"api    1/1     Running   0          60m   10.0.0.9   node-1   <none>           <none>            <none>\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+
+	buf.Reset()
+	if err := (Formatter{EmptyPlaceholder: "-"}).printPodTableWide(&buf, &pods, now); err != nil {
+		t.Fatal(err)
+	}
+	expect = "" +
+		"NAME   READY   STATUS    RESTARTS   AGE   IP         NODE     NOMINATED NODE   READINESS GATES   RESTART-REASON\n" +
+		"web    0/1     Pending   0          60s   -          -        node-2           0/2               -\n" +
+		"api    1/1     Running   0          60m   10.0.0.9   node-1   -                -                 -\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
+
+func TestFormatterPrintPodTableWide(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pods := apiv1.PodList{Items: []apiv1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-frontend-deployment-7d4b9c8f5b-x7p2q", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status:     apiv1.PodStatus{Phase: "Pending"},
+	}}}
+
+	var buf bytes.Buffer
+	f := Formatter{EmptyPlaceholder: "-", MaxNameWidth: 10}
+	if err := f.printPodTableWide(&buf, &pods, now); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"NAME         READY   STATUS    RESTARTS   AGE   IP    NODE   NOMINATED NODE   READINESS GATES   RESTART-REASON\n" +
+		"web-front…   0/1     Pending   0          60m   -     -      -                -                 -\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
+
+func TestPrintPodTableWideQOS(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limits := apiv1.ResourceList{apiv1.ResourceCPU: resource.MustParse("1"), apiv1.ResourceMemory: resource.MustParse("1Gi")}
+	pods := apiv1.PodList{Items: []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: []apiv1.Container{{Resources: apiv1.ResourceRequirements{Requests: limits, Limits: limits}}}},
+			Status:     apiv1.PodStatus{Phase: "Pending"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: "Pending"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	f := DefaultFormatter
+	f.ShowQOS = true
+	if err := f.printPodTableWide(&buf, &pods, now); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"NAME   READY   STATUS    RESTARTS   AGE   IP       NODE     NOMINATED NODE   READINESS GATES   RESTART-REASON   QOS\n" +
+		"db     0/1     Pending   0          60m   <none>   node-1   <none>           <none>            <none>           Guaranteed\n" +
+		"job    0/1     Pending   0          60m   <none>   node-1   <none>           <none>            <none>           BestEffort\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
+
+func TestPrintPodTableWideRestartReason(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	restarted := func(name, reason string, exitCode int32) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase: "Running",
+				PodIP: "10.0.0.9",
+				ContainerStatuses: []apiv1.ContainerStatus{
+					{
+						Ready:                true,
+						RestartCount:         1,
+						State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+						LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: reason, ExitCode: exitCode, FinishedAt: metav1.NewTime(now.Add(-5 * time.Minute))}},
+					},
+				},
+			},
+		}
+	}
+	pods := apiv1.PodList{Items: []apiv1.Pod{
+		restarted("db", "OOMKilled", 137),
+		restarted("api", "Error", 1),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: "Pending"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := DefaultFormatter.printPodTableWide(&buf, &pods, now); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"NAME   READY   STATUS    RESTARTS     AGE   IP         NODE     NOMINATED NODE   READINESS GATES   RESTART-REASON\n" +
+		"db     1/1     Running   1 (5m ago)   60m   10.0.0.9   node-1   <none>           <none>            OOMKilled\n" +
+		"api    1/1     Running   1 (5m ago)   60m   10.0.0.9   node-1   <none>           <none>            Error\n" +
+		"web    0/1     Pending   0            60m   <none>     node-1   <none>           <none>            <none>\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
+
+func TestWritePodDetail(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		Spec: apiv1.PodSpec{
+			InitContainers: []apiv1.Container{{Name: "migrate"}},
+			Containers:     []apiv1.Container{{Name: "app"}, {Name: "sidecar"}, {Name: "worker"}},
+		},
+		Status: apiv1.PodStatus{
+			Phase: "Running",
+			InitContainerStatuses: []apiv1.ContainerStatus{
+				{Name: "migrate", State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed"}}},
+			},
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{
+					Name:         "app",
+					RestartCount: 4,
+					State:        apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: apiv1.ContainerState{
+						Terminated: &apiv1.ContainerStateTerminated{ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-2 * time.Minute))},
+					},
+				},
+				{Name: "sidecar", Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+				{Name: "worker", RestartCount: 1, State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Signal: 9, ExitCode: 137}}},
+			},
+			EphemeralContainerStatuses: []apiv1.ContainerStatus{
+				{Name: "debugger", State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{}}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writePodDetail(&buf, &pod, now); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"web   1/3   CrashLoopBackOff   5 (2m ago)   60m\n" +
+		"  └ migrate: Terminated(Completed, exit 0) restarts=0\n" +
+		"  └ app: Waiting(CrashLoopBackOff) restarts=4\n" +
+		"  └ sidecar: Running restarts=0\n" +
+		"  └ worker: Terminated(Signal:9, exit 137) restarts=1\n" +
+		"  └ debugger: Waiting restarts=0\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
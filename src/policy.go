@@ -0,0 +1,136 @@
+package main
+
+import (
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// UsesLatestTag returns the names of the init and regular containers whose
+// image is tagged ":latest" or carries no tag at all. Images pinned by digest
+// are reproducible and never reported.
+func UsesLatestTag(pod *apiv1.Pod) []string {
+	var names []string
+	for _, containers := range [][]apiv1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			if imageIsLatest(container.Image) {
+				names = append(names, container.Name)
+			}
+		}
+	}
+	return names
+}
+
+func imageIsLatest(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	// only the last path element can carry a tag, a colon before it is a
+	// registry port
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i < 0 || name[i+1:] == "latest"
+}
+
+// SecurityProfile returns the pod-level fsGroup and runAsNonRoot, and the
+// pod-level seccomp profile rendered as "RuntimeDefault", "Unconfined",
+// "Localhost:<name>" or "<unset>". Container-level overrides are not applied.
+func SecurityProfile(pod *apiv1.Pod) (fsGroup *int64, seccomp string, runAsNonRoot *bool) {
+	securityContext := pod.Spec.SecurityContext
+	if securityContext == nil {
+		return nil, "<unset>", nil
+	}
+	return securityContext.FSGroup, printSeccompProfile(securityContext.SeccompProfile), securityContext.RunAsNonRoot
+}
+
+func printSeccompProfile(profile *apiv1.SeccompProfile) string {
+	if profile == nil {
+		return "<unset>"
+	}
+	switch profile.Type {
+	case apiv1.SeccompProfileTypeLocalhost:
+		name := ""
+		if profile.LocalhostProfile != nil {
+			name = *profile.LocalhostProfile
+		}
+		return "Localhost:" + name
+	case apiv1.SeccompProfileTypeRuntimeDefault, apiv1.SeccompProfileTypeUnconfined:
+		return string(profile.Type)
+	default:
+		return "<unset>"
+	}
+}
+
+// ImageDriftEntry is a container running another image than its spec asks for.
+type ImageDriftEntry struct {
+	Container string
+	Desired   string
+	Running   string
+}
+
+// ImageDrift returns the containers of pod whose running image differs from
+// the spec, e.g. mid-rollout. Images are compared after expanding Docker Hub
+// short names, as the runtime reports "nginx" as "docker.io/library/nginx".
+func ImageDrift(pod *apiv1.Pod) []ImageDriftEntry {
+	running := make(map[string]string)
+	for _, status := range pod.Status.ContainerStatuses {
+		running[status.Name] = status.Image
+	}
+
+	var drift []ImageDriftEntry
+	for _, container := range pod.Spec.Containers {
+		image, ok := running[container.Name]
+		if !ok || image == "" {
+			continue
+		}
+		if normalizeImage(container.Image) != normalizeImage(image) {
+			drift = append(drift, ImageDriftEntry{Container: container.Name, Desired: container.Image, Running: image})
+		}
+	}
+	return drift
+}
+
+// normalizeImage expands an image reference without a registry to its Docker
+// Hub form.
+func normalizeImage(image string) string {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return "docker.io/library/" + image
+	}
+	// the first element is a registry only if it looks like a host
+	if registry := image[:i]; !strings.ContainsAny(registry, ".:") && registry != "localhost" {
+		return "docker.io/" + image
+	}
+	return image
+}
+
+// ComputeQOS returns the QoS class of pod from the cpu and memory requests and
+// limits of its init and regular containers: BestEffort when none sets any,
+// Guaranteed when every container sets both limits with equal requests, and
+// Burstable otherwise. An unset request defaults to the limit, as the API
+// server does.
+func ComputeQOS(pod *apiv1.Pod) apiv1.PodQOSClass {
+	bestEffort, guaranteed := true, true
+	for _, containers := range [][]apiv1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			for _, name := range []apiv1.ResourceName{apiv1.ResourceCPU, apiv1.ResourceMemory} {
+				request, hasRequest := container.Resources.Requests[name]
+				limit, hasLimit := container.Resources.Limits[name]
+				if hasRequest && !request.IsZero() || hasLimit && !limit.IsZero() {
+					bestEffort = false
+				}
+				if !hasLimit || hasRequest && request.Cmp(limit) != 0 {
+					guaranteed = false
+				}
+			}
+		}
+	}
+	switch {
+	case bestEffort:
+		return apiv1.PodQOSBestEffort
+	case guaranteed:
+		return apiv1.PodQOSGuaranteed
+	default:
+		return apiv1.PodQOSBurstable
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUsesLatestTag(t *testing.T) {
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test1"},
+		Spec: apiv1.PodSpec{
+			InitContainers: []apiv1.Container{
+				{Name: "migrate", Image: "registry.example.com:5000/migrate"},
+			},
+			Containers: []apiv1.Container{
+				{Name: "web", Image: "nginx:latest"},
+				{Name: "api", Image: "registry.example.com:5000/api:1.4.2"},
+				{Name: "cache", Image: "redis"},
+				{Name: "proxy", Image: "envoyproxy/envoy@sha256:3c4a1e7d5b9f0c2e8a6d4b1f7e3c9a5d2b8f6e4c1a7d3b9e5f2c8a6d4b1e7f3c"},
+			},
+		},
+	}
+
+	expect := []string{"migrate", "web", "cache"}
+	names := UsesLatestTag(&pod)
+	if !reflect.DeepEqual(expect, names) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, names))
+	}
+}
+
+func TestSecurityProfile(t *testing.T) {
+	fsGroup := int64(2000)
+	localhostProfile := "profiles/audit.json"
+
+	tests := []struct {
+		securityContext    *apiv1.PodSecurityContext
+		expectFSGroup      *int64
+		expectSeccomp      string
+		expectRunAsNonRoot *bool
+	}{
+		{nil, nil, "<unset>", nil},
+		{&apiv1.PodSecurityContext{}, nil, "<unset>", nil},
+		{
+			&apiv1.PodSecurityContext{
+				FSGroup:        &fsGroup,
+				RunAsNonRoot:   &trueVal,
+				SeccompProfile: &apiv1.SeccompProfile{Type: apiv1.SeccompProfileTypeRuntimeDefault},
+			},
+			&fsGroup,
+			"RuntimeDefault",
+			&trueVal,
+		},
+		{
+			&apiv1.PodSecurityContext{
+				RunAsNonRoot:   &falseVal,
+				SeccompProfile: &apiv1.SeccompProfile{Type: apiv1.SeccompProfileTypeUnconfined},
+			},
+			nil,
+			"Unconfined",
+			&falseVal,
+		},
+		{
+			&apiv1.PodSecurityContext{
+				SeccompProfile: &apiv1.SeccompProfile{Type: apiv1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile},
+			},
+			nil,
+			"Localhost:profiles/audit.json",
+			nil,
+		},
+	}
+
+	for i, test := range tests {
+		pod := apiv1.Pod{Spec: apiv1.PodSpec{SecurityContext: test.securityContext}}
+		fsGroup, seccomp, runAsNonRoot := SecurityProfile(&pod)
+		if !reflect.DeepEqual(test.expectFSGroup, fsGroup) || seccomp != test.expectSeccomp || !reflect.DeepEqual(test.expectRunAsNonRoot, runAsNonRoot) {
+			t.Errorf("%d mismatch: got (%v, %q, %v), expected (%v, %q, %v)", i, fsGroup, seccomp, runAsNonRoot, test.expectFSGroup, test.expectSeccomp, test.expectRunAsNonRoot)
+		}
+	}
+}
+
+func TestImageDrift(t *testing.T) {
+	pod := func(statuses ...apiv1.ContainerStatus) apiv1.Pod {
+		return apiv1.Pod{
+			Spec: apiv1.PodSpec{
+				Containers: []apiv1.Container{
+					{Name: "web", Image: "nginx:1.25"},
+					{Name: "proxy", Image: "example/proxy:v2"},
+					{Name: "agent", Image: "registry.example.com:5000/agent:3.1"},
+				},
+			},
+			Status: apiv1.PodStatus{ContainerStatuses: statuses},
+		}
+	}
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect []ImageDriftEntry
+	}{
+		{
+			// Test images matching once normalized
+			pod(
+				apiv1.ContainerStatus{Name: "web", Image: "docker.io/library/nginx:1.25"},
+				apiv1.ContainerStatus{Name: "proxy", Image: "docker.io/example/proxy:v2"},
+				apiv1.ContainerStatus{Name: "agent", Image: "registry.example.com:5000/agent:3.1"},
+			),
+			nil,
+		},
+		{
+			// Test containers still running the previous images
+			pod(
+				apiv1.ContainerStatus{Name: "web", Image: "docker.io/library/nginx:1.24"},
+				apiv1.ContainerStatus{Name: "proxy", Image: "docker.io/example/proxy:v2"},
+				apiv1.ContainerStatus{Name: "agent", Image: "registry.example.com:5000/agent:3.0"},
+			),
+			[]ImageDriftEntry{
+				{Container: "web", Desired: "nginx:1.25", Running: "docker.io/library/nginx:1.24"},
+				{Container: "agent", Desired: "registry.example.com:5000/agent:3.1", Running: "registry.example.com:5000/agent:3.0"},
+			},
+		},
+		{
+			// Test containers without a status yet
+			pod(),
+			nil,
+		},
+	}
+
+	for i, test := range tests {
+		drift := ImageDrift(&test.pod)
+		if !reflect.DeepEqual(test.expect, drift) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, drift))
+		}
+	}
+}
+
+func TestComputeQOS(t *testing.T) {
+	resources := func(requests, limits string) apiv1.ResourceRequirements {
+		var requirements apiv1.ResourceRequirements
+		if requests != "" {
+			requirements.Requests = apiv1.ResourceList{apiv1.ResourceCPU: resource.MustParse(requests), apiv1.ResourceMemory: resource.MustParse(requests + "Mi")}
+		}
+		if limits != "" {
+			requirements.Limits = apiv1.ResourceList{apiv1.ResourceCPU: resource.MustParse(limits), apiv1.ResourceMemory: resource.MustParse(limits + "Mi")}
+		}
+		return requirements
+	}
+
+	tests := []struct {
+		initContainers []apiv1.Container
+		containers     []apiv1.Container
+		expect         apiv1.PodQOSClass
+	}{
+		{
+			// Test no requests or limits anywhere
+			[]apiv1.Container{{Name: "init"}},
+			[]apiv1.Container{{Name: "app"}, {Name: "sidecar"}},
+			apiv1.PodQOSBestEffort,
+		},
+		{
+			// Test requests equal to limits on every container
+			[]apiv1.Container{{Name: "init", Resources: resources("1", "1")}},
+			[]apiv1.Container{{Name: "app", Resources: resources("2", "2")}, {Name: "sidecar", Resources: resources("", "1")}},
+			apiv1.PodQOSGuaranteed,
+		},
+		{
+			// Test one container without resources next to guaranteed ones
+			nil,
+			[]apiv1.Container{{Name: "app", Resources: resources("2", "2")}, {Name: "sidecar"}},
+			apiv1.PodQOSBurstable,
+		},
+		{
+			// Test requests below limits
+			nil,
+			[]apiv1.Container{{Name: "app", Resources: resources("1", "2")}},
+			apiv1.PodQOSBurstable,
+		},
+		{
+			// Test guaranteed containers with a burstable init container
+			[]apiv1.Container{{Name: "init", Resources: resources("1", "")}},
+			[]apiv1.Container{{Name: "app", Resources: resources("2", "2")}},
+			apiv1.PodQOSBurstable,
+		},
+	}
+
+	for i, test := range tests {
+		pod := apiv1.Pod{Spec: apiv1.PodSpec{InitContainers: test.initContainers, Containers: test.containers}}
+		qos := ComputeQOS(&pod)
+		if !reflect.DeepEqual(test.expect, qos) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, qos))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// Printer renders pod columns against a single clock, so a whole render pass
+// can be frozen in time without threading now through every call.
+type Printer struct {
+	// Now returns the current time; time.Now when nil.
+	Now func() time.Time
+}
+
+func (p Printer) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+// Reason is the STATUS column of pod.
+func (p Printer) Reason(pod *apiv1.Pod) string {
+	return printReason(pod)
+}
+
+// Ready is the READY column of pod, e.g. "1/2".
+func (p Printer) Ready(pod *apiv1.Pod) string {
+	return printReady(pod)
+}
+
+// Restarts is the RESTARTS column of pod, e.g. "3 (5m ago)".
+func (p Printer) Restarts(pod *apiv1.Pod) string {
+	return printRestarts(pod, p.now())
+}
+
+// Age is the AGE column of pod.
+func (p Printer) Age(pod *apiv1.Pod) string {
+	return PodAge(pod, p.now())
+}
+
+// Summarize returns the PodSummary of pod.
+func (p Printer) Summarize(pod *apiv1.Pod) PodSummary {
+	return Summarize(pod, p.now())
+}
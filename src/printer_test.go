@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPrinter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-3 * time.Hour))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase: "Running",
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{
+					Ready:        true,
+					RestartCount: 2,
+					State:        apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+					LastTerminationState: apiv1.ContainerState{
+						Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-5 * time.Minute))},
+					},
+				},
+			},
+		},
+	}
+
+	p := Printer{Now: func() time.Time { return now }}
+	if got := p.Age(&pod); got != "3h" {
+		t.Errorf("mismatch: got %q, expected %q", got, "3h")
+	}
+	if got := p.Restarts(&pod); got != "2 (5m ago)" {
+		t.Errorf("mismatch: got %q, expected %q", got, "2 (5m ago)")
+	}
+	if got := p.Summarize(&pod).Age; got != "3h" {
+		t.Errorf("mismatch: got %q, expected %q", got, "3h")
+	}
+	if got := p.Reason(&pod); got != "Running" {
+		t.Errorf("mismatch: got %q, expected %q", got, "Running")
+	}
+	if got := p.Ready(&pod); got != "1/1" {
+		t.Errorf("mismatch: got %q, expected %q", got, "1/1")
+	}
+
+	if got := (Printer{}).Age(&pod); got == "3h" {
+		t.Errorf("expected a nil Now to use the wall clock, got %q", got)
+	}
+}
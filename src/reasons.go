@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const (
+	ReasonCategoryImage      = "Image"
+	ReasonCategoryConfig     = "Config"
+	ReasonCategoryCrash      = "Crash"
+	ReasonCategoryResource   = "Resource"
+	ReasonCategoryScheduling = "Scheduling"
+	ReasonCategoryNetwork    = "Network"
+	ReasonCategoryOther      = "Other"
+)
+
+var reasonCategories = map[string]string{
+	"ErrImagePull":               ReasonCategoryImage,
+	"ImagePullBackOff":           ReasonCategoryImage,
+	"InvalidImageName":           ReasonCategoryImage,
+	"ErrImageNeverPull":          ReasonCategoryImage,
+	"ImageInspectError":          ReasonCategoryImage,
+	"RegistryUnavailable":        ReasonCategoryImage,
+	"CreateContainerConfigError": ReasonCategoryConfig,
+	"CreateContainerError":       ReasonCategoryConfig,
+	"RunContainerError":          ReasonCategoryConfig,
+	"CrashLoopBackOff":           ReasonCategoryCrash,
+	"Error":                      ReasonCategoryCrash,
+	"ContainerCannotRun":         ReasonCategoryCrash,
+	"StartError":                 ReasonCategoryCrash,
+	"OOMKilled":                  ReasonCategoryResource,
+	"Evicted":                    ReasonCategoryResource,
+	"OutOfcpu":                   ReasonCategoryResource,
+	"OutOfmemory":                ReasonCategoryResource,
+	"OutOfpods":                  ReasonCategoryResource,
+	"SchedulingGated":            ReasonCategoryScheduling,
+	"Unschedulable":              ReasonCategoryScheduling,
+	"NodeAffinity":               ReasonCategoryScheduling,
+	"NodeLost":                   ReasonCategoryNetwork,
+	"NetworkNotReady":            ReasonCategoryNetwork,
+}
+
+// KnownReasons lists every reason ReasonCategory classifies, in the order
+// dashboards should present them.
+var KnownReasons = []string{
+	"ErrImagePull",
+	"ImagePullBackOff",
+	"InvalidImageName",
+	"ErrImageNeverPull",
+	"ImageInspectError",
+	"RegistryUnavailable",
+	"CreateContainerConfigError",
+	"CreateContainerError",
+	"RunContainerError",
+	"CrashLoopBackOff",
+	"Error",
+	"ContainerCannotRun",
+	"StartError",
+	"OOMKilled",
+	"Evicted",
+	"OutOfcpu",
+	"OutOfmemory",
+	"OutOfpods",
+	"SchedulingGated",
+	"Unschedulable",
+	"NodeAffinity",
+	"NodeLost",
+	"NetworkNotReady",
+}
+
+// ReasonCategory classifies a reason as produced by printReason. The "Init:"
+// prefix is ignored, synthesized "Signal:N" and "ExitCode:N" reasons count as
+// crashes and anything unrecognized is "Other".
+func ReasonCategory(reason string) string {
+	reason = strings.TrimPrefix(reason, "Init:")
+	if category, ok := reasonCategories[reason]; ok {
+		return category
+	}
+	if strings.HasPrefix(reason, "Signal:") || strings.HasPrefix(reason, "ExitCode:") {
+		return ReasonCategoryCrash
+	}
+	return ReasonCategoryOther
+}
+
+// Severity buckets a reason by how much attention it needs. Higher values need
+// more attention; Unknown comes last because a reason nobody classified yet
+// deserves a look.
+type Severity int
+
+const (
+	Healthy Severity = iota
+	Pending
+	Warning
+	Error
+	Unknown
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Healthy:
+		return "Healthy"
+	case Pending:
+		return "Pending"
+	case Warning:
+		return "Warning"
+	case Error:
+		return "Error"
+	case Unknown:
+		return "Unknown"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+var pendingReasons = map[string]bool{
+	"Pending":           true,
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+	"SchedulingGated":   true,
+}
+
+var warningReasons = map[string]bool{
+	"NotReady":    true,
+	"Terminating": true,
+}
+
+// ClassifyReason maps a reason as produced by printReason to a Severity.
+// Image, config, crash and resource failures are errors, including when they
+// happen in an init container, and scheduling or network trouble is a
+// warning. Reasons it does not recognize are Unknown.
+func ClassifyReason(reason string) Severity {
+	switch reason {
+	case "Running", "Completed", string(apiv1.PodSucceeded):
+		return Healthy
+	case string(apiv1.PodFailed):
+		return Error
+	}
+	if pendingReasons[reason] || isInitProgress(reason) {
+		return Pending
+	}
+	if warningReasons[reason] {
+		return Warning
+	}
+	switch ReasonCategory(reason) {
+	case ReasonCategoryImage, ReasonCategoryConfig, ReasonCategoryCrash, ReasonCategoryResource:
+		return Error
+	case ReasonCategoryScheduling, ReasonCategoryNetwork:
+		return Warning
+	}
+	return Unknown
+}
+
+// isInitProgress reports whether reason has the "Init:M/N" form.
+func isInitProgress(reason string) bool {
+	var done, total int
+	n, err := fmt.Sscanf(reason, "Init:%d/%d", &done, &total)
+	return err == nil && n == 2
+}
+
+// LifecycleStage places pod in one of the coarse stages Scheduling,
+// Initializing, Starting, Running, Terminating and Terminated, for funnel
+// views where printReason is too detailed. A pod is Starting until its Ready
+// condition is True.
+func LifecycleStage(pod *apiv1.Pod) string {
+	switch {
+	case isPodPhaseTerminal(pod.Status.Phase):
+		return "Terminated"
+	case pod.DeletionTimestamp != nil:
+		return "Terminating"
+	case pod.Spec.NodeName == "":
+		return "Scheduling"
+	case computePodStatus(pod).initializing:
+		return "Initializing"
+	case !hasPodReadyCondition(pod.Status.Conditions):
+		return "Starting"
+	default:
+		return "Running"
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReasonCategory(t *testing.T) {
+	tests := []struct {
+		reason string
+		expect string
+	}{
+		{"ImagePullBackOff", ReasonCategoryImage},
+		{"CreateContainerConfigError", ReasonCategoryConfig},
+		{"CrashLoopBackOff", ReasonCategoryCrash},
+		{"Init:CrashLoopBackOff", ReasonCategoryCrash},
+		{"Signal:9", ReasonCategoryCrash},
+		{"Init:ExitCode:137", ReasonCategoryCrash},
+		{"OOMKilled", ReasonCategoryResource},
+		{"SchedulingGated", ReasonCategoryScheduling},
+		{"NodeLost", ReasonCategoryNetwork},
+		{"Running", ReasonCategoryOther},
+		{"SomethingNew", ReasonCategoryOther},
+	}
+
+	for i, test := range tests {
+		category := ReasonCategory(test.reason)
+		if !reflect.DeepEqual(test.expect, category) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, category))
+		}
+	}
+
+	if len(KnownReasons) != len(reasonCategories) {
+		t.Errorf("KnownReasons has %d entries but %d reasons are categorized", len(KnownReasons), len(reasonCategories))
+	}
+	for _, reason := range KnownReasons {
+		if ReasonCategory(reason) == ReasonCategoryOther {
+			t.Errorf("known reason %q is not categorized", reason)
+		}
+	}
+}
+
+func TestClassifyReason(t *testing.T) {
+	tests := []struct {
+		reason string
+		expect Severity
+	}{
+		{"Running", Healthy},
+		{"Completed", Healthy},
+		{"Succeeded", Healthy},
+		{"Pending", Pending},
+		{"ContainerCreating", Pending},
+		{"PodInitializing", Pending},
+		{"Init:1/3", Pending},
+		{"SchedulingGated", Pending},
+		{"CrashLoopBackOff", Error},
+		{"Init:CrashLoopBackOff", Error},
+		{"Error", Error},
+		{"OOMKilled", Error},
+		{"Evicted", Error},
+		{"ImagePullBackOff", Error},
+		{"Signal:9", Error},
+		{"Failed", Error},
+		{"NotReady", Warning},
+		{"Terminating", Warning},
+		{"NodeLost", Warning},
+		{"Unknown", Unknown},
+		{"SomethingNew", Unknown},
+		{"Init:SomethingNew", Unknown},
+	}
+
+	for i, test := range tests {
+		severity := ClassifyReason(test.reason)
+		if !reflect.DeepEqual(test.expect, severity) {
+			t.Errorf("%d %q mismatch: %s", i, test.reason, cmp.Diff(test.expect, severity))
+		}
+	}
+}
+
+func TestClassifyReasonCoversPrintReason(t *testing.T) {
+	// every reason printReason synthesizes or passes through from the phase,
+	// besides the container reasons listed in KnownReasons
+	emitted := []string{
+		"Pending", "Running", "Succeeded", "Failed", "Completed", "NotReady", "Terminating",
+		"SchedulingGated", "ContainerCreating", "PodInitializing", "NodeLost",
+		"Init:0/1", "Init:2/3", "Init:Signal:9", "Init:ExitCode:1", "Init:Error", "Init:CrashLoopBackOff",
+		"Signal:15", "ExitCode:2",
+	}
+	for _, reason := range append(emitted, KnownReasons...) {
+		if severity := ClassifyReason(reason); severity == Unknown {
+			t.Errorf("reason %q emitted by printReason is not classified", reason)
+		}
+	}
+
+	// printReason emits "Unknown" for phase Unknown and for a deleted pod on
+	// an unreachable node; it is the one reason meant to classify as Unknown
+	if severity := ClassifyReason("Unknown"); severity != Unknown {
+		t.Errorf("reason %q emitted by printReason classified as %v, expected %v", "Unknown", severity, Unknown)
+	}
+}
+
+func TestLifecycleStage(t *testing.T) {
+	deleted := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ready := []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionTrue}}
+	running := apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}
+	scheduled := apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)}
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect string
+	}{
+		{
+			apiv1.Pod{
+				Spec:   apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{Phase: apiv1.PodPending},
+			},
+			"Scheduling",
+		},
+		{
+			apiv1.Pod{
+				Spec: apiv1.PodSpec{NodeName: "node-1", InitContainers: make([]apiv1.Container, 1), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase:                 apiv1.PodPending,
+					InitContainerStatuses: []apiv1.ContainerStatus{{State: running}},
+				},
+			},
+			"Initializing",
+		},
+		{
+			apiv1.Pod{
+				Spec: scheduled,
+				Status: apiv1.PodStatus{
+					Phase:             apiv1.PodPending,
+					ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ContainerCreating"}}}},
+				},
+			},
+			"Starting",
+		},
+		{
+			apiv1.Pod{
+				Spec:   scheduled,
+				Status: apiv1.PodStatus{Phase: apiv1.PodRunning, Conditions: ready, ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: running}}},
+			},
+			"Running",
+		},
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted},
+				Spec:       scheduled,
+				Status:     apiv1.PodStatus{Phase: apiv1.PodRunning, Conditions: ready, ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: running}}},
+			},
+			"Terminating",
+		},
+		{
+			apiv1.Pod{
+				Spec:   scheduled,
+				Status: apiv1.PodStatus{Phase: apiv1.PodSucceeded},
+			},
+			"Terminated",
+		},
+		{
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted},
+				Spec:       scheduled,
+				Status:     apiv1.PodStatus{Phase: apiv1.PodFailed},
+			},
+			"Terminated",
+		},
+	}
+
+	for i, test := range tests {
+		if stage := LifecycleStage(&test.pod); stage != test.expect {
+			t.Errorf("%d mismatch: got %q, expected %q", i, stage, test.expect)
+		}
+	}
+}
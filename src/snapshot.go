@@ -0,0 +1,70 @@
+package main
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// PhaseTransitions maps the names of the pods present in both snapshots whose
+// phase changed to their old and new phase.
+func PhaseTransitions(old, new []apiv1.Pod) map[string][2]apiv1.PodPhase {
+	oldPhases := make(map[string]apiv1.PodPhase, len(old))
+	for _, pod := range old {
+		oldPhases[pod.Name] = pod.Status.Phase
+	}
+
+	transitions := map[string][2]apiv1.PodPhase{}
+	for _, pod := range new {
+		oldPhase, ok := oldPhases[pod.Name]
+		if ok && oldPhase != pod.Status.Phase {
+			transitions[pod.Name] = [2]apiv1.PodPhase{oldPhase, pod.Status.Phase}
+		}
+	}
+	return transitions
+}
+
+// phaseHealth orders phases from worst to best for spotting regressions.
+var phaseHealth = map[apiv1.PodPhase]int{
+	apiv1.PodFailed:    0,
+	apiv1.PodUnknown:   1,
+	apiv1.PodPending:   2,
+	apiv1.PodRunning:   3,
+	apiv1.PodSucceeded: 3,
+}
+
+// Regressions keeps the transitions that made a pod worse off, e.g. Running to
+// Failed.
+func Regressions(transitions map[string][2]apiv1.PodPhase) map[string][2]apiv1.PodPhase {
+	regressions := map[string][2]apiv1.PodPhase{}
+	for name, phases := range transitions {
+		if phaseHealth[phases[1]] < phaseHealth[phases[0]] {
+			regressions[name] = phases
+		}
+	}
+	return regressions
+}
+
+// MergePriorStatus returns cur with the init and regular container statuses of
+// prev carried forward where cur reports none, so a pod observed mid-update
+// keeps rendering its last known row instead of flickering to 0/N. prev must
+// be the same pod, by UID; otherwise, and when nothing is missing, cur is
+// returned as is. cur itself is never modified.
+func MergePriorStatus(prev, cur *apiv1.Pod) *apiv1.Pod {
+	if prev == nil || cur == nil || prev.UID != cur.UID {
+		return cur
+	}
+	carryContainers := len(cur.Status.ContainerStatuses) == 0 && len(prev.Status.ContainerStatuses) > 0
+	carryInitContainers := len(cur.Status.InitContainerStatuses) == 0 && len(prev.Status.InitContainerStatuses) > 0
+	if !carryContainers && !carryInitContainers {
+		return cur
+	}
+
+	merged := cur.DeepCopy()
+	prior := prev.Status.DeepCopy()
+	if carryContainers {
+		merged.Status.ContainerStatuses = prior.ContainerStatuses
+	}
+	if carryInitContainers {
+		merged.Status.InitContainerStatuses = prior.InitContainerStatuses
+	}
+	return merged
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPhaseTransitions(t *testing.T) {
+	pod := func(name string, phase apiv1.PodPhase) apiv1.Pod {
+		return apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: apiv1.PodStatus{Phase: phase}}
+	}
+	old := []apiv1.Pod{
+		pod("web", apiv1.PodRunning),
+		pod("api", apiv1.PodPending),
+		pod("cache", apiv1.PodRunning),
+		pod("gone", apiv1.PodRunning),
+	}
+	new := []apiv1.Pod{
+		pod("web", apiv1.PodFailed),
+		pod("api", apiv1.PodRunning),
+		pod("cache", apiv1.PodRunning),
+		pod("added", apiv1.PodPending),
+	}
+
+	transitions := PhaseTransitions(old, new)
+	expect := map[string][2]apiv1.PodPhase{
+		"web": {apiv1.PodRunning, apiv1.PodFailed},
+		"api": {apiv1.PodPending, apiv1.PodRunning},
+	}
+	if !reflect.DeepEqual(expect, transitions) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, transitions))
+	}
+
+	regressions := Regressions(transitions)
+	expect = map[string][2]apiv1.PodPhase{
+		"web": {apiv1.PodRunning, apiv1.PodFailed},
+	}
+	if !reflect.DeepEqual(expect, regressions) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, regressions))
+	}
+}
+
+func TestMergePriorStatus(t *testing.T) {
+	running := []apiv1.ContainerStatus{{Name: "app", Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}}
+	pod := func(uid string, statuses []apiv1.ContainerStatus) *apiv1.Pod {
+		return &apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", UID: types.UID(uid)},
+			Spec:       apiv1.PodSpec{Containers: []apiv1.Container{{Name: "app"}}},
+			Status:     apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: statuses},
+		}
+	}
+
+	tests := []struct {
+		prev   *apiv1.Pod
+		cur    *apiv1.Pod
+		expect []apiv1.ContainerStatus
+	}{
+		// Test empty statuses are filled from the same pod
+		{pod("uid-1", running), pod("uid-1", nil), running},
+		// Test a recreated pod keeps its own empty statuses
+		{pod("uid-1", running), pod("uid-2", nil), nil},
+		// Test current statuses win
+		{pod("uid-1", running), pod("uid-1", []apiv1.ContainerStatus{{Name: "app"}}), []apiv1.ContainerStatus{{Name: "app"}}},
+		// Test no prior observation
+		{nil, pod("uid-1", nil), nil},
+	}
+
+	for i, test := range tests {
+		merged := MergePriorStatus(test.prev, test.cur)
+		if !reflect.DeepEqual(test.expect, merged.Status.ContainerStatuses) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, merged.Status.ContainerStatuses))
+		}
+	}
+
+	cur := pod("uid-1", nil)
+	merged := MergePriorStatus(pod("uid-1", running), cur)
+	if cur.Status.ContainerStatuses != nil {
+		t.Errorf("expected cur to be left untouched")
+	}
+	if ready := printReady(merged); ready != "1/1" {
+		t.Errorf("mismatch: got %q, expected %q", ready, "1/1")
+	}
+}
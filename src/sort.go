@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// triageRank orders severities for triage, most urgent first. Error outranks
+// Unknown here, unlike the Severity values themselves.
+var triageRank = map[Severity]int{
+	Error:   4,
+	Unknown: 3,
+	Warning: 2,
+	Pending: 1,
+	Healthy: 0,
+}
+
+// podTriageRank ranks pod by the ClassifyReason severity of its reason.
+func podTriageRank(pod *apiv1.Pod) int {
+	return triageRank[ClassifyReason(printReason(pod))]
+}
+
+// PodsByHealth sorts pods most broken first: by the ClassifyReason severity
+// of their reason, then by restart count descending, then by name.
+type PodsByHealth struct {
+	Pods []apiv1.Pod
+	Now  time.Time
+	// Escalation, when set, ranks pods it reports stuck at Now as Warning
+	// instead of Pending.
+	Escalation *EscalationOptions
+}
+
+func (p PodsByHealth) rank(pod *apiv1.Pod) int {
+	if p.Escalation != nil && strings.HasSuffix(p.Escalation.EscalatedStatus(pod, p.Now), " (stuck)") {
+		return triageRank[Warning]
+	}
+	return podTriageRank(pod)
+}
+
+func (p PodsByHealth) Len() int      { return len(p.Pods) }
+func (p PodsByHealth) Swap(i, j int) { p.Pods[i], p.Pods[j] = p.Pods[j], p.Pods[i] }
+
+func (p PodsByHealth) Less(i, j int) bool {
+	a, b := &p.Pods[i], &p.Pods[j]
+	if rankA, rankB := p.rank(a), p.rank(b); rankA != rankB {
+		return rankA > rankB
+	}
+	if restartsA, restartsB := computePodStatus(a).restarts, computePodStatus(b).restarts; restartsA != restartsB {
+		return restartsA > restartsB
+	}
+	return a.Name < b.Name
+}
+
+// SortPodsByHealth sorts pods in place with PodsByHealth.
+func SortPodsByHealth(pods []apiv1.Pod, now time.Time) {
+	sort.Sort(PodsByHealth{Pods: pods, Now: now})
+}
+
+// podLess compares two pods on a single column; a tie falls back to the name.
+type podLess func(a, b *apiv1.Pod) (less, equal bool)
+
+var podSortKeys = map[string]podLess{
+	"name": func(a, b *apiv1.Pod) (bool, bool) {
+		return a.Name < b.Name, a.Name == b.Name
+	},
+	"status": func(a, b *apiv1.Pod) (bool, bool) {
+		reasonA, reasonB := printReason(a), printReason(b)
+		return reasonA < reasonB, reasonA == reasonB
+	},
+	// newest first, so the oldest pods end up last
+	"age": func(a, b *apiv1.Pod) (bool, bool) {
+		createdA, createdB := a.CreationTimestamp.Time, b.CreationTimestamp.Time
+		return createdA.After(createdB), createdA.Equal(createdB)
+	},
+	"restarts": func(a, b *apiv1.Pod) (bool, bool) {
+		restartsA, restartsB := computePodStatus(a).restarts, computePodStatus(b).restarts
+		return restartsA < restartsB, restartsA == restartsB
+	},
+	"ready": func(a, b *apiv1.Pod) (bool, bool) {
+		statusA, statusB := computePodStatus(a), computePodStatus(b)
+		if statusA.readyContainers != statusB.readyContainers {
+			return statusA.readyContainers < statusB.readyContainers, false
+		}
+		return statusA.totalContainers < statusB.totalContainers, statusA.totalContainers == statusB.totalContainers
+	},
+}
+
+// SortPods stably sorts pods in place by one of the columns name, status,
+// age, restarts or ready, breaking ties by name.
+func SortPods(pods []apiv1.Pod, by string) error {
+	return SortPodsDir(pods, by, false)
+}
+
+// SortPodsDir is SortPods with the order of the key reversed when descending
+// is set, e.g. oldest first for age. Ties are still broken by name.
+func SortPodsDir(pods []apiv1.Pod, by string, descending bool) error {
+	less, ok := podSortKeys[by]
+	if !ok {
+		return fmt.Errorf("unknown sort key %q, expected one of: name, status, age, restarts, ready", by)
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		if isLess, equal := less(&pods[i], &pods[j]); !equal {
+			return isLess != descending
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	return nil
+}
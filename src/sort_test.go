@@ -0,0 +1,147 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSortPodsByHealth(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	running := func(name string, restarts int32) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             "Running",
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, RestartCount: restarts, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		}
+	}
+	waiting := func(name, reason string, restarts int32) apiv1.Pod {
+		pod := running(name, restarts)
+		pod.Status.ContainerStatuses[0] = apiv1.ContainerStatus{RestartCount: restarts, State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: reason}}}
+		return pod
+	}
+	pending := func(name string, age time.Duration) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: "Pending"},
+		}
+	}
+
+	pods := []apiv1.Pod{
+		running("web-b", 0),
+		pending("queued", time.Minute),
+		waiting("api-b", "CrashLoopBackOff", 3),
+		running("web-a", 0),
+		waiting("api-a", "CrashLoopBackOff", 3),
+		waiting("worker", "ImagePullBackOff", 0),
+		pending("stuck", time.Hour),
+		waiting("batch", "CrashLoopBackOff", 12),
+		running("cache", 2),
+	}
+
+	sorted := append([]apiv1.Pod(nil), pods...)
+	SortPodsByHealth(sorted, now)
+
+	expect := []string{"batch", "api-a", "api-b", "worker", "queued", "stuck", "cache", "web-a", "web-b"}
+	if names := podNames(sorted); !reflect.DeepEqual(expect, names) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, names))
+	}
+
+	sort.Sort(PodsByHealth{Pods: pods, Now: now, Escalation: &EscalationOptions{StuckAfter: 10 * time.Minute}})
+
+	expect = []string{"batch", "api-a", "api-b", "worker", "stuck", "queued", "cache", "web-a", "web-b"}
+	if names := podNames(pods); !reflect.DeepEqual(expect, names) {
+		t.Errorf("escalated mismatch: %s", cmp.Diff(expect, names))
+	}
+}
+
+func TestSortPods(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(name string, age time.Duration, restarts int32, ready bool, waitingReason string) apiv1.Pod {
+		status := apiv1.ContainerStatus{Ready: ready, RestartCount: restarts, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}
+		if waitingReason != "" {
+			status.State = apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: waitingReason}}
+		}
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: apiv1.PodRunning, ContainerStatuses: []apiv1.ContainerStatus{status}},
+		}
+	}
+	pods := []apiv1.Pod{
+		pod("web", time.Hour, 0, true, ""),
+		pod("cache", time.Minute, 10, false, "CrashLoopBackOff"),
+		pod("api", time.Hour, 2, true, ""),
+		pod("worker", 24*time.Hour, 2, false, "ImagePullBackOff"),
+	}
+
+	tests := []struct {
+		by     string
+		expect []string
+	}{
+		{"name", []string{"api", "cache", "web", "worker"}},
+		{"status", []string{"cache", "worker", "api", "web"}},
+		{"age", []string{"cache", "api", "web", "worker"}},
+		{"restarts", []string{"web", "api", "worker", "cache"}},
+		{"ready", []string{"cache", "worker", "api", "web"}},
+	}
+
+	for i, test := range tests {
+		sorted := append([]apiv1.Pod(nil), pods...)
+		if err := SortPods(sorted, test.by); err != nil {
+			t.Fatalf("%d unexpected error: %v", i, err)
+		}
+		if names := podNames(sorted); !reflect.DeepEqual(test.expect, names) {
+			t.Errorf("%d %q mismatch: %s", i, test.by, cmp.Diff(test.expect, names))
+		}
+	}
+
+	if err := SortPods(pods, "node"); err == nil {
+		t.Errorf("expected an error for an unknown sort key")
+	}
+}
+
+func TestSortPodsDir(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(name string, age time.Duration) apiv1.Pod {
+		return apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+	}
+	pods := []apiv1.Pod{
+		pod("web", time.Hour),
+		pod("cache", time.Minute),
+		pod("api", time.Hour),
+		pod("worker", 24*time.Hour),
+	}
+
+	tests := []struct {
+		descending bool
+		expect     []string
+	}{
+		{false, []string{"cache", "api", "web", "worker"}},
+		// ties stay ordered by name
+		{true, []string{"worker", "api", "web", "cache"}},
+	}
+
+	for i, test := range tests {
+		sorted := append([]apiv1.Pod(nil), pods...)
+		if err := SortPodsDir(sorted, "age", test.descending); err != nil {
+			t.Fatalf("%d unexpected error: %v", i, err)
+		}
+		if names := podNames(sorted); !reflect.DeepEqual(test.expect, names) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, names))
+		}
+	}
+
+	if err := SortPodsDir(pods, "node", true); err == nil {
+		t.Errorf("expected an error for an unknown sort key")
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// podSummarySchemaVersion identifies the shape of PodSummary. Bump it whenever
+// a field is added, removed or renamed so consumers pinned with
+// --output-version notice the change.
+const podSummarySchemaVersion = "v2"
+
+// PodSummary is the computed status of a pod as structured data.
+type PodSummary struct {
+	SchemaVersion string     `json:"schemaVersion"`
+	Name          string     `json:"name"`
+	Namespace     string     `json:"namespace"`
+	Reason        string     `json:"reason"`
+	Ready         string     `json:"ready"`
+	ReadyCount    int        `json:"readyCount"`
+	TotalCount    int        `json:"totalCount"`
+	Restarts      int        `json:"restarts"`
+	LastRestart   *time.Time `json:"lastRestart,omitempty"`
+	Age           string     `json:"age"`
+}
+
+// Summarize computes the current PodSummary of pod, its age taken at now.
+func Summarize(pod *apiv1.Pod, now time.Time) PodSummary {
+	status := computePodStatus(pod)
+	summary := PodSummary{
+		SchemaVersion: podSummarySchemaVersion,
+		Name:          pod.Name,
+		Namespace:     pod.Namespace,
+		Reason:        status.reason,
+		Ready:         fmt.Sprintf("%d/%d", status.readyContainers, status.totalContainers),
+		ReadyCount:    status.readyContainers,
+		TotalCount:    status.totalContainers,
+		Restarts:      status.restarts,
+		Age:           PodAge(pod, now),
+	}
+	if !status.lastRestartDate.IsZero() {
+		lastRestart := status.lastRestartDate.Time
+		summary.LastRestart = &lastRestart
+	}
+	return summary
+}
+
+// podSummaryV1 is the original shape of the summary, still rendered for
+// consumers pinned with --output-version=v1.
+type podSummaryV1 struct {
+	SchemaVersion string `json:"schemaVersion"`
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	Reason        string `json:"reason"`
+}
+
+// newPodSummary builds the summary for pod in the requested schema version.
+// An empty version selects the current one.
+func newPodSummary(pod *apiv1.Pod, version string, now time.Time) (interface{}, error) {
+	switch version {
+	case "", podSummarySchemaVersion:
+		return Summarize(pod, now), nil
+	case "v1":
+		return podSummaryV1{
+			SchemaVersion: version,
+			Name:          pod.Name,
+			Namespace:     pod.Namespace,
+			Reason:        printReason(pod),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output version %q, supported: %q, %q", version, "v1", podSummarySchemaVersion)
+	}
+}
+
+// SummaryYAML renders the summary of pod in schema version, the latest when
+// empty, as YAML for embedding in larger reports. The keys match the JSON
+// output.
+func SummaryYAML(pod *apiv1.Pod, version string, now time.Time) ([]byte, error) {
+	summary, err := newPodSummary(pod, version, now)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(summary)
+}
+
+// SummaryChanged reports whether the displayed status of a pod differs between
+// two observations, returning both. A nil oldPod is an add and always counts as
+// a change from "".
+func SummaryChanged(oldPod, newPod *apiv1.Pod, now time.Time) (changed bool, from, to string) {
+	if oldPod != nil {
+		from = Summarize(oldPod, now).Reason
+	}
+	if newPod != nil {
+		to = Summarize(newPod, now).Reason
+	}
+	return oldPod == nil || from != to, from, to
+}
+
+// SummarizeStatuses counts pods by the reason printReason shows for them, so
+// the totals reconcile with the table rows.
+func SummarizeStatuses(pods *apiv1.PodList) map[string]int {
+	counts := map[string]int{}
+	for i := range pods.Items {
+		counts[printReason(&pods.Items[i])]++
+	}
+	return counts
+}
+
+// FormatSummary joins counts as "5 Running, 2 CrashLoopBackOff, 1 Pending",
+// largest count first and ties ordered by reason.
+func FormatSummary(counts map[string]int) string {
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%d %s", counts[reason], reason)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CountByReason counts pods by the reason Summarize computes for them.
+func CountByReason(pods []apiv1.Pod, now time.Time) map[string]int {
+	counts := map[string]int{}
+	for i := range pods {
+		counts[Summarize(&pods[i], now).Reason]++
+	}
+	return counts
+}
+
+// CountBySeverity counts pods by the ClassifyReason severity of their reason.
+func CountBySeverity(pods []apiv1.Pod, now time.Time) map[Severity]int {
+	counts := map[Severity]int{}
+	for i := range pods {
+		counts[ClassifyReason(Summarize(&pods[i], now).Reason)]++
+	}
+	return counts
+}
+
+// NodeReasonMatrix counts pods by reason per node, for a nodes by reasons
+// heatmap. Unscheduled pods are counted under "<none>".
+func NodeReasonMatrix(pods []apiv1.Pod) map[string]map[string]int {
+	matrix := map[string]map[string]int{}
+	for i := range pods {
+		node := DefaultFormatter.valueOrEmpty(pods[i].Spec.NodeName)
+		if matrix[node] == nil {
+			matrix[node] = map[string]int{}
+		}
+		matrix[node][printReason(&pods[i])]++
+	}
+	return matrix
+}
+
+// MaxNodeConcentration returns the node hosting the largest share of pods and
+// that share as a fraction of all of them, unscheduled pods included. A high
+// share means the pods are poorly spread, e.g. despite anti-affinity. Ties go
+// to the node that sorts first; the node is "" when no pod is scheduled.
+func MaxNodeConcentration(pods []apiv1.Pod) (string, float64) {
+	counts := map[string]int{}
+	for i := range pods {
+		if node := pods[i].Spec.NodeName; node != "" {
+			counts[node]++
+		}
+	}
+	maxNode, maxCount := "", 0
+	for node, count := range counts {
+		if count > maxCount || count == maxCount && node < maxNode {
+			maxNode, maxCount = node, count
+		}
+	}
+	if maxCount == 0 {
+		return "", 0
+	}
+	return maxNode, float64(maxCount) / float64(len(pods))
+}
+
+// InlineStatusCounts renders the SummarizeStatuses counts as space-separated
+// "reason:count" tokens, e.g. "Running:18 Pending:1 CrashLoopBackOff:1",
+// ordered from healthy to severe and then by reason.
+func InlineStatusCounts(pods []apiv1.Pod) string {
+	counts := SummarizeStatuses(&apiv1.PodList{Items: pods})
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if severityI, severityJ := ClassifyReason(reasons[i]), ClassifyReason(reasons[j]); severityI != severityJ {
+			return severityI < severityJ
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	tokens := make([]string, len(reasons))
+	for i, reason := range reasons {
+		tokens[i] = fmt.Sprintf("%s:%d", reason, counts[reason])
+	}
+	return strings.Join(tokens, " ")
+}
+
+// WaitingReasonCounts counts pods by the reason of their first waiting
+// container, init containers first, e.g. ImagePullBackOff or
+// ContainerCreating. Pods without a waiting container are left out.
+func WaitingReasonCounts(pods []apiv1.Pod) map[string]int {
+	counts := map[string]int{}
+	for _, pod := range pods {
+		if reason := firstWaitingReason(&pod); reason != "" {
+			counts[reason]++
+		}
+	}
+	return counts
+}
+
+func firstWaitingReason(pod *apiv1.Pod) string {
+	for _, statuses := range [][]apiv1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+				return status.State.Waiting.Reason
+			}
+		}
+	}
+	return ""
+}
+
+// statusChange is the webhook payload written by ChangePayload.
+type statusChange struct {
+	Pod       string    `json:"pod"`
+	UID       string    `json:"uid"`
+	OldStatus string    `json:"oldStatus"`
+	NewStatus string    `json:"newStatus"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ChangePayload returns a compact JSON payload describing the change of pod's
+// status from oldStatus, for POSTing to a webhook. It returns nil, nil when
+// the status did not change.
+func ChangePayload(pod *apiv1.Pod, oldStatus string) ([]byte, error) {
+	return changePayload(pod, oldStatus, time.Now())
+}
+
+func changePayload(pod *apiv1.Pod, oldStatus string, now time.Time) ([]byte, error) {
+	newStatus := printReason(pod)
+	if newStatus == oldStatus {
+		return nil, nil
+	}
+	return json.Marshal(statusChange{
+		Pod:       types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}.String(),
+		UID:       string(pod.UID),
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+		Timestamp: now.UTC(),
+	})
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestNewPodSummarySchemaVersion(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test1", Namespace: "ns"},
+		Status:     apiv1.PodStatus{Phase: apiv1.PodRunning},
+	}
+
+	tests := []struct {
+		version   string
+		expect    string
+		expectErr bool
+	}{
+		{"", podSummarySchemaVersion, false},
+		{"v1", "v1", false},
+		{"v2", "v2", false},
+		{"v0", "", true},
+	}
+
+	for i, test := range tests {
+		summary, err := newPodSummary(&pod, test.version, now)
+		if (err != nil) != test.expectErr {
+			t.Fatalf("%d unexpected error: %v", i, err)
+		}
+		if test.expectErr {
+			continue
+		}
+		fields := summaryFields(t, summary)
+		if !reflect.DeepEqual(test.expect, fields["schemaVersion"]) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, fields["schemaVersion"]))
+		}
+	}
+
+	summary, err := newPodSummary(&pod, "v1", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"schemaVersion": "v1",
+		"name":          "test1",
+		"namespace":     "ns",
+		"reason":        "Running",
+	}
+	if fields := summaryFields(t, summary); !reflect.DeepEqual(expect, fields) {
+		t.Errorf("v1 mismatch: %s", cmp.Diff(expect, fields))
+	}
+}
+
+func summaryFields(t *testing.T, summary interface{}) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+// containerPod returns a single-container pod in phase whose container is in
+// state, ready when it is running.
+func containerPod(phase apiv1.PodPhase, state apiv1.ContainerState) apiv1.Pod {
+	return apiv1.Pod{
+		Spec:   apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{Phase: phase, ContainerStatuses: []apiv1.ContainerStatus{{Ready: state.Running != nil, State: state}}},
+	}
+}
+
+var (
+	runningState   = apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}
+	crashLoopState = apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	// pendingPod is a single-container pod that has no container status yet.
+	pendingPod = apiv1.Pod{Spec: apiv1.PodSpec{Containers: make([]apiv1.Container, 1)}, Status: apiv1.PodStatus{Phase: apiv1.PodPending}}
+)
+
+func TestSummarizeJSON(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+		Status: apiv1.PodStatus{
+			Phase: apiv1.PodRunning,
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+				{
+					RestartCount:         7,
+					State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(Summarize(&pod, now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"schemaVersion": podSummarySchemaVersion,
+		"name":          "web",
+		"namespace":     "prod",
+		"reason":        "CrashLoopBackOff",
+		"ready":         "1/2",
+		"readyCount":    float64(1),
+		"totalCount":    float64(2),
+		"restarts":      float64(7),
+		"lastRestart":   "2023-12-31T23:59:00Z",
+		"age":           "120m",
+	}
+	if !reflect.DeepEqual(expect, fields) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, fields))
+	}
+}
+
+func TestSummaryYAML(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase: apiv1.PodRunning,
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{
+					RestartCount:         3,
+					State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+				},
+			},
+		},
+	}
+
+	data, err := SummaryYAML(&pod, "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "reason: CrashLoopBackOff\n") || !strings.Contains(string(data), "readyCount: 0\n") {
+		t.Errorf("expected the JSON keys in:\n%s", data)
+	}
+
+	var summary PodSummary
+	if err := yaml.Unmarshal(data, &summary); err != nil {
+		t.Fatal(err)
+	}
+	expect := Summarize(&pod, now)
+	if !reflect.DeepEqual(expect, summary) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, summary))
+	}
+
+	data, err = SummaryYAML(&pod, "v1", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectV1 := "name: web\nnamespace: prod\nreason: CrashLoopBackOff\nschemaVersion: v1\n"
+	if string(data) != expectV1 {
+		t.Errorf("mismatch: %s", cmp.Diff(expectV1, string(data)))
+	}
+
+	if _, err := SummaryYAML(&pod, "v0", now); err == nil {
+		t.Error("expected an error for an unsupported output version")
+	}
+}
+
+func TestSummaryChanged(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	running := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase:             apiv1.PodRunning,
+			ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+		},
+	}
+	resynced := running.DeepCopy()
+	resynced.ResourceVersion = "2"
+	crashing := running.DeepCopy()
+	crashing.Status.ContainerStatuses[0] = apiv1.ContainerStatus{
+		RestartCount: 1,
+		State:        apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}
+
+	tests := []struct {
+		old, new      *apiv1.Pod
+		expectChanged bool
+		expectFrom    string
+		expectTo      string
+	}{
+		{nil, running, true, "", "Running"},
+		{running, resynced, false, "Running", "Running"},
+		{running, crashing, true, "Running", "CrashLoopBackOff"},
+	}
+
+	for i, test := range tests {
+		changed, from, to := SummaryChanged(test.old, test.new, now)
+		if changed != test.expectChanged || from != test.expectFrom || to != test.expectTo {
+			t.Errorf("%d mismatch: got (%v, %q, %q), expected (%v, %q, %q)", i, changed, from, to, test.expectChanged, test.expectFrom, test.expectTo)
+		}
+	}
+}
+
+func TestSummarizeStatuses(t *testing.T) {
+	running := apiv1.Pod{
+		Spec: apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase:             apiv1.PodRunning,
+			ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+		},
+	}
+	crashing := apiv1.Pod{
+		Spec: apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase:             apiv1.PodRunning,
+			ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+		},
+	}
+	pending := apiv1.Pod{
+		Spec:   apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{Phase: apiv1.PodPending},
+	}
+	evicted := apiv1.Pod{Status: apiv1.PodStatus{Phase: apiv1.PodFailed, Reason: "Evicted"}}
+	pods := &apiv1.PodList{Items: []apiv1.Pod{running, crashing, pending, running, evicted, crashing, running}}
+
+	counts := SummarizeStatuses(pods)
+	expect := map[string]int{"Running": 3, "CrashLoopBackOff": 2, "Pending": 1, "Evicted": 1}
+	if !reflect.DeepEqual(expect, counts) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, counts))
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if total != len(pods.Items) {
+		t.Errorf("counts add up to %d, expected %d", total, len(pods.Items))
+	}
+
+	summary := FormatSummary(counts)
+	if expect := "3 Running, 2 CrashLoopBackOff, 1 Evicted, 1 Pending"; summary != expect {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, summary))
+	}
+	if summary := FormatSummary(nil); summary != "" {
+		t.Errorf("expected an empty summary, got %q", summary)
+	}
+}
+
+func TestChangePayload(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod", UID: "6f1c2a9e"},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase:             apiv1.PodRunning,
+			ContainerStatuses: []apiv1.ContainerStatus{{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+		},
+	}
+
+	payload, err := changePayload(&pod, "Running", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"pod":"prod/api","uid":"6f1c2a9e","oldStatus":"Running","newStatus":"CrashLoopBackOff","timestamp":"2024-01-01T00:00:00Z"}`
+	if string(payload) != expect {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, string(payload)))
+	}
+
+	payload, err = changePayload(&pod, "CrashLoopBackOff", now)
+	if payload != nil || err != nil {
+		t.Errorf("got (%s, %v) for an unchanged status, expected (nil, nil)", payload, err)
+	}
+}
+
+func TestWaitingReasonCounts(t *testing.T) {
+	waiting := func(reason string) apiv1.ContainerStatus {
+		return apiv1.ContainerStatus{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: reason}}}
+	}
+	running := apiv1.ContainerStatus{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}
+	pod := func(initStatuses []apiv1.ContainerStatus, statuses ...apiv1.ContainerStatus) apiv1.Pod {
+		return apiv1.Pod{Status: apiv1.PodStatus{InitContainerStatuses: initStatuses, ContainerStatuses: statuses}}
+	}
+	pods := []apiv1.Pod{
+		pod(nil, waiting("ImagePullBackOff")),
+		pod(nil, running, waiting("CrashLoopBackOff"), waiting("ImagePullBackOff")),
+		pod(nil, waiting("ContainerCreating")),
+		pod(nil, waiting("CrashLoopBackOff")),
+		pod([]apiv1.ContainerStatus{waiting("ImagePullBackOff")}, waiting("PodInitializing")),
+		pod(nil, running),
+		pod(nil),
+	}
+
+	expect := map[string]int{"ImagePullBackOff": 2, "CrashLoopBackOff": 2, "ContainerCreating": 1}
+	counts := WaitingReasonCounts(pods)
+	if !reflect.DeepEqual(expect, counts) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, counts))
+	}
+}
+
+func TestInlineStatusCounts(t *testing.T) {
+	running := containerPod(apiv1.PodRunning, runningState)
+	crashing := containerPod(apiv1.PodRunning, crashLoopState)
+	pulling := containerPod(apiv1.PodPending, apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ImagePullBackOff"}})
+	pending := pendingPod
+	done := containerPod(apiv1.PodSucceeded, apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed"}})
+
+	tests := []struct {
+		pods   []apiv1.Pod
+		expect string
+	}{
+		{[]apiv1.Pod{crashing, running, pending, running, pulling, done, running}, "Completed:1 Running:3 Pending:1 CrashLoopBackOff:1 ImagePullBackOff:1"},
+		{nil, ""},
+	}
+
+	for i, test := range tests {
+		counts := InlineStatusCounts(test.pods)
+		if !reflect.DeepEqual(test.expect, counts) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, counts))
+		}
+	}
+}
+
+func TestCountByReasonAndSeverity(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	running := containerPod(apiv1.PodRunning, runningState)
+	completed := containerPod(apiv1.PodSucceeded, apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Completed"}})
+	crashing := containerPod(apiv1.PodRunning, crashLoopState)
+	terminating := containerPod(apiv1.PodRunning, runningState)
+	terminating.DeletionTimestamp = &metav1.Time{Time: now}
+	pods := []apiv1.Pod{running, completed, crashing, terminating, running, crashing}
+
+	reasons := CountByReason(pods, now)
+	expectReasons := map[string]int{"Running": 2, "Completed": 1, "CrashLoopBackOff": 2, "Terminating": 1}
+	if !reflect.DeepEqual(expectReasons, reasons) {
+		t.Errorf("mismatch: %s", cmp.Diff(expectReasons, reasons))
+	}
+
+	severities := CountBySeverity(pods, now)
+	expectSeverities := map[Severity]int{Healthy: 3, Error: 2, Warning: 1}
+	if !reflect.DeepEqual(expectSeverities, severities) {
+		t.Errorf("mismatch: %s", cmp.Diff(expectSeverities, severities))
+	}
+}
+
+func TestNodeReasonMatrix(t *testing.T) {
+	pod := func(node string, phase apiv1.PodPhase, state apiv1.ContainerState) apiv1.Pod {
+		pod := containerPod(phase, state)
+		pod.Spec.NodeName = node
+		return pod
+	}
+	running := runningState
+	crashing := crashLoopState
+	oomKilled := apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+	pods := []apiv1.Pod{
+		pod("node-1", apiv1.PodRunning, running),
+		pod("node-1", apiv1.PodRunning, running),
+		pod("node-2", apiv1.PodRunning, crashing),
+		pod("node-2", apiv1.PodRunning, oomKilled),
+		pod("node-2", apiv1.PodRunning, crashing),
+		pod("node-2", apiv1.PodRunning, running),
+		pendingPod,
+	}
+
+	expect := map[string]map[string]int{
+		"node-1": {"Running": 2},
+		"node-2": {"CrashLoopBackOff": 2, "OOMKilled": 1, "Running": 1},
+		"<none>": {"Pending": 1},
+	}
+	matrix := NodeReasonMatrix(pods)
+	if !reflect.DeepEqual(expect, matrix) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, matrix))
+	}
+}
+
+func TestMaxNodeConcentration(t *testing.T) {
+	onNode := func(node string) apiv1.Pod {
+		return apiv1.Pod{Spec: apiv1.PodSpec{NodeName: node}}
+	}
+
+	tests := []struct {
+		pods        []apiv1.Pod
+		expectNode  string
+		expectShare float64
+	}{
+		{[]apiv1.Pod{onNode("node-1"), onNode("node-1"), onNode("node-2"), onNode("node-1"), onNode("node-1")}, "node-1", 0.8},
+		{[]apiv1.Pod{onNode("node-2"), onNode("node-1"), onNode(""), onNode("")}, "node-1", 0.25},
+		{[]apiv1.Pod{onNode("")}, "", 0},
+		{nil, "", 0},
+	}
+
+	for i, test := range tests {
+		node, share := MaxNodeConcentration(test.pods)
+		if node != test.expectNode || share != test.expectShare {
+			t.Errorf("%d mismatch: got (%q, %v), expected (%q, %v)", i, node, share, test.expectNode, test.expectShare)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// tabwriter settings used by kubectl's printers
+const (
+	tabwriterMinWidth = 6
+	tabwriterWidth    = 4
+	tabwriterPadding  = 3
+	tabwriterPadChar  = ' '
+	tabwriterFlags    = 0
+)
+
+func newTabWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, tabwriterMinWidth, tabwriterWidth, tabwriterPadding, tabwriterPadChar, tabwriterFlags)
+}
+
+// NameTruncateMode selects where an over-long pod name is cut.
+type NameTruncateMode int
+
+const (
+	// TruncateTrailing keeps the start of the name.
+	TruncateTrailing NameTruncateMode = iota
+	// TruncateMiddle keeps the start and the end of the name, so the
+	// controller hash stays visible, e.g. "web-front…-x7p2".
+	TruncateMiddle
+)
+
+// Formatter holds the render options shared by the pod printers.
+type Formatter struct {
+	// EmptyPlaceholder is shown for columns without a value, e.g. the node of
+	// a pod that is not scheduled yet.
+	EmptyPlaceholder string
+	// MaxNameWidth truncates longer pod names with an ellipsis; 0 disables
+	// truncation.
+	MaxNameWidth     int
+	NameTruncateMode NameTruncateMode
+	// ShowQOS adds a QOS column to the wide table.
+	ShowQOS bool
+	// ShowHealth adds a HEALTH column with HealthPoints to the wide table.
+	ShowHealth bool
+	// ReadySeparator joins the ready and total container counts of the READY
+	// column, e.g. "|" or " of "; "/" when empty.
+	ReadySeparator string
+	// RestartingWithin appends " (restarting)" to the STATUS column of pods
+	// with a container that terminated within that long, see ReasonOptions;
+	// 0 disables the suffix.
+	RestartingWithin time.Duration
+}
+
+// DefaultFormatter renders like kubectl.
+var DefaultFormatter = Formatter{
+	EmptyPlaceholder: "<none>",
+	ReadySeparator:   "/",
+}
+
+// PodTableRow is a row of the default "kubectl get pods" table.
+type PodTableRow struct {
+	Name     string
+	Ready    string
+	Status   string
+	Restarts string
+	Age      string
+}
+
+func NewPodTableRow(pod *apiv1.Pod, now time.Time) PodTableRow {
+	return DefaultFormatter.NewPodTableRow(pod, now)
+}
+
+func (f Formatter) NewPodTableRow(pod *apiv1.Pod, now time.Time) PodTableRow {
+	return PodTableRow{
+		Name:     f.truncateName(pod.Name),
+		Ready:    f.printReady(pod),
+		Status:   podStatusReasonWithOptions(pod, ReasonOptions{RestartingWithin: f.RestartingWithin}, now),
+		Restarts: printRestarts(pod, now),
+		Age:      PodAge(pod, now),
+	}
+}
+
+func (f Formatter) printReady(pod *apiv1.Pod) string {
+	separator := f.ReadySeparator
+	if separator == "" {
+		separator = "/"
+	}
+	status := computePodStatus(pod)
+	return fmt.Sprintf("%d%s%d", status.readyContainers, separator, status.totalContainers)
+}
+
+func (f Formatter) truncateName(name string) string {
+	runes := []rune(name)
+	if f.MaxNameWidth <= 0 || len(runes) <= f.MaxNameWidth {
+		return name
+	}
+	kept := f.MaxNameWidth - 1
+	if f.NameTruncateMode == TruncateMiddle {
+		suffix := kept / 2
+		return string(runes[:kept-suffix]) + "…" + string(runes[len(runes)-suffix:])
+	}
+	return string(runes[:kept]) + "…"
+}
+
+// FormatPodTable renders pods like "kubectl get pods", header included.
+func FormatPodTable(pods []apiv1.Pod, now time.Time) string {
+	return DefaultFormatter.FormatPodTable(pods, now)
+}
+
+func (f Formatter) FormatPodTable(pods []apiv1.Pod, now time.Time) string {
+	return f.formatPodTable(pods, now)
+}
+
+// extraColumn is an optional column rendered between RESTARTS and AGE.
+type extraColumn struct {
+	header string
+	value  func(pod *apiv1.Pod, now time.Time) string
+}
+
+func (f Formatter) formatPodTable(pods []apiv1.Pod, now time.Time, extra ...extraColumn) string {
+	var buf bytes.Buffer
+	tw := newTabWriter(&buf)
+	f.writePodRows(tw, pods, now, true, extra...)
+	tw.Flush()
+	return buf.String()
+}
+
+// WritePodRows writes pods into a caller-owned tabwriter without flushing it,
+// so they can share one alignment with other sections of a report.
+func WritePodRows(tw *tabwriter.Writer, pods []apiv1.Pod, now time.Time, writeHeader bool) error {
+	return DefaultFormatter.writePodRows(tw, pods, now, writeHeader)
+}
+
+func (f Formatter) writePodRows(tw *tabwriter.Writer, pods []apiv1.Pod, now time.Time, writeHeader bool, extra ...extraColumn) error {
+	if writeHeader {
+		header := "NAME\tREADY\tSTATUS\tRESTARTS\t"
+		for _, column := range extra {
+			header += column.header + "\t"
+		}
+		if _, err := fmt.Fprintln(tw, header+"AGE"); err != nil {
+			return err
+		}
+	}
+	for i := range pods {
+		row := f.NewPodTableRow(&pods[i], now)
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t", row.Name, row.Ready, row.Status, row.Restarts)
+		for _, column := range extra {
+			line += column.value(&pods[i], now) + "\t"
+		}
+		if _, err := fmt.Fprintln(tw, line+row.Age); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PodTableRowWide is a row of "kubectl get pods -o wide".
+type PodTableRowWide struct {
+	PodTableRow
+	IP             string
+	Node           string
+	NominatedNode  string
+	ReadinessGates string
+	RestartReason  string
+}
+
+func NewPodTableRowWide(pod *apiv1.Pod, now time.Time) PodTableRowWide {
+	return DefaultFormatter.NewPodTableRowWide(pod, now)
+}
+
+func (f Formatter) NewPodTableRowWide(pod *apiv1.Pod, now time.Time) PodTableRowWide {
+	return PodTableRowWide{
+		PodTableRow:    f.NewPodTableRow(pod, now),
+		IP:             f.valueOrEmpty(pod.Status.PodIP),
+		Node:           f.valueOrEmpty(pod.Spec.NodeName),
+		NominatedNode:  f.valueOrEmpty(pod.Status.NominatedNodeName),
+		ReadinessGates: f.printReadinessGates(pod),
+		RestartReason:  f.valueOrEmpty(RestartCause(pod)),
+	}
+}
+
+// printReadinessGates renders how many readiness gates have a True condition,
+// e.g. "1/2", or EmptyPlaceholder when the pod declares none.
+func (f Formatter) printReadinessGates(pod *apiv1.Pod) string {
+	if len(pod.Spec.ReadinessGates) == 0 {
+		return f.EmptyPlaceholder
+	}
+	trueConditions := 0
+	for _, readinessGate := range pod.Spec.ReadinessGates {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == readinessGate.ConditionType && condition.Status == apiv1.ConditionTrue {
+				trueConditions++
+				break
+			}
+		}
+	}
+	return fmt.Sprintf("%d/%d", trueConditions, len(pod.Spec.ReadinessGates))
+}
+
+func (f Formatter) valueOrEmpty(value string) string {
+	if value == "" {
+		return f.EmptyPlaceholder
+	}
+	return value
+}
+
+// ExtractField returns the value of a single column for pod, for scripts that
+// want one value without a header. Supported fields are name, status, ready,
+// restarts, age, ip and node.
+func ExtractField(pod *apiv1.Pod, field string, now time.Time) (string, error) {
+	return DefaultFormatter.ExtractField(pod, field, now)
+}
+
+func (f Formatter) ExtractField(pod *apiv1.Pod, field string, now time.Time) (string, error) {
+	switch field {
+	case "name":
+		return pod.Name, nil
+	case "status":
+		return printReason(pod), nil
+	case "ready":
+		return f.printReady(pod), nil
+	case "restarts":
+		return printRestarts(pod, now), nil
+	case "age":
+		return PodAge(pod, now), nil
+	case "ip":
+		return f.valueOrEmpty(pod.Status.PodIP), nil
+	case "node":
+		return f.valueOrEmpty(pod.Spec.NodeName), nil
+	default:
+		return "", fmt.Errorf("unknown field %q, expected one of: name, status, ready, restarts, age, ip, node", field)
+	}
+}
+
+// FormatPodTableWithRecentRestarts renders the default table with an extra
+// RESTARTS(<window>) column computed by RestartsInWindow.
+func FormatPodTableWithRecentRestarts(pods []apiv1.Pod, now time.Time, window time.Duration) string {
+	return DefaultFormatter.formatPodTable(pods, now, extraColumn{
+		header: fmt.Sprintf("RESTARTS(%s)", formatWindow(window)),
+		value: func(pod *apiv1.Pod, now time.Time) string {
+			return strconv.Itoa(RestartsInWindow(pod, window, now))
+		},
+	})
+}
+
+// formatWindow renders d without zero trailing units, e.g. "1h" or "1m30s".
+func formatWindow(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// DebugIDs returns the fields that help correlate a pod with an informer
+// cache: its generation and resourceVersion.
+func DebugIDs(pod *apiv1.Pod) (generation int64, resourceVersion string) {
+	return pod.Generation, pod.ResourceVersion
+}
+
+// FormatPodTableDebug renders the default table with GENERATION and
+// RESOURCE-VERSION columns for diagnosing stale caches.
+func FormatPodTableDebug(pods []apiv1.Pod, now time.Time) string {
+	return DefaultFormatter.formatPodTable(pods, now,
+		extraColumn{
+			header: "GENERATION",
+			value: func(pod *apiv1.Pod, _ time.Time) string {
+				generation, _ := DebugIDs(pod)
+				return strconv.FormatInt(generation, 10)
+			},
+		},
+		extraColumn{
+			header: "RESOURCE-VERSION",
+			value: func(pod *apiv1.Pod, _ time.Time) string {
+				_, resourceVersion := DebugIDs(pod)
+				return DefaultFormatter.valueOrEmpty(resourceVersion)
+			},
+		},
+	)
+}
+
+// PrintPodTable writes pods to w like "kubectl get pods", followed by a
+// FormatSummary line when showSummary is set.
+func PrintPodTable(w io.Writer, pods *apiv1.PodList, showSummary bool) error {
+	return DefaultFormatter.PrintPodTable(w, pods, showSummary)
+}
+
+// PrintPodTable writes pods to w like "kubectl get pods", rendered with the
+// options of f, followed by a FormatSummary line when showSummary is set.
+func (f Formatter) PrintPodTable(w io.Writer, pods *apiv1.PodList, showSummary bool) error {
+	if _, err := io.WriteString(w, f.FormatPodTable(pods.Items, time.Now())); err != nil {
+		return err
+	}
+	return writeSummaryLine(w, pods, showSummary)
+}
+
+// writeSummaryLine writes the FormatSummary line that follows a table when
+// showSummary is set and there are pods.
+func writeSummaryLine(w io.Writer, pods *apiv1.PodList, showSummary bool) error {
+	if !showSummary || len(pods.Items) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "\n%s\n", FormatSummary(SummarizeStatuses(pods)))
+	return err
+}
+
+// PrintPodTableAllNamespaces writes pods to w like "kubectl get pods -A", with
+// a leading NAMESPACE column, followed by a FormatSummary line when
+// showSummary is set. Pods without a namespace read "default".
+func PrintPodTableAllNamespaces(w io.Writer, pods *apiv1.PodList, showSummary bool) error {
+	return DefaultFormatter.PrintPodTableAllNamespaces(w, pods, showSummary)
+}
+
+// PrintPodTableAllNamespaces writes pods to w like "kubectl get pods -A", with
+// a leading NAMESPACE column, rendered with the options of f.
+func (f Formatter) PrintPodTableAllNamespaces(w io.Writer, pods *apiv1.PodList, showSummary bool) error {
+	if err := f.printPodTableAllNamespaces(w, pods, time.Now()); err != nil {
+		return err
+	}
+	return writeSummaryLine(w, pods, showSummary)
+}
+
+func (f Formatter) printPodTableAllNamespaces(w io.Writer, pods *apiv1.PodList, now time.Time) error {
+	tw := newTabWriter(w)
+	if _, err := fmt.Fprintln(tw, "NAMESPACE\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE"); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		namespace := pods.Items[i].Namespace
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+		row := f.NewPodTableRow(&pods.Items[i], now)
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", namespace, row.Name, row.Ready, row.Status, row.Restarts, row.Age); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
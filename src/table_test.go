@@ -0,0 +1,572 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewPodTableRow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect PodTableRow
+	}{
+		{
+			// Test running pod with a restart
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test1", CreationTimestamp: metav1.NewTime(now.Add(-3 * time.Hour))},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							Ready:                true,
+							RestartCount:         3,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-5 * time.Minute))}},
+						},
+						{Ready: false, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			PodTableRow{Name: "test1", Ready: "1/2", Status: "Running", Restarts: "3 (5m ago)", Age: "3h"},
+		},
+		{
+			// Test initializing pod counts init container restarts
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test2", CreationTimestamp: metav1.NewTime(now.Add(-90 * time.Second))},
+				Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 2), Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Pending",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{}}},
+						{
+							RestartCount:         1,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-10 * time.Second))}},
+						},
+					},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+					},
+				},
+			},
+			PodTableRow{Name: "test2", Ready: "0/1", Status: "Init:1/2", Restarts: "1 (10s ago)", Age: "90s"},
+		},
+		{
+			// Test started sidecar counts toward ready and its restarts are kept once initialized
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test3", CreationTimestamp: metav1.NewTime(now.Add(-10 * time.Minute))},
+				Spec: apiv1.PodSpec{
+					InitContainers: []apiv1.Container{
+						{Name: "setup"},
+						{Name: "sidecar", RestartPolicy: &containerRestartPolicyAlways},
+					},
+					Containers: []apiv1.Container{{Name: "app"}},
+				},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					InitContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "setup", RestartCount: 2, State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{}}},
+						{
+							Name:                 "sidecar",
+							Ready:                true,
+							Started:              &trueVal,
+							RestartCount:         1,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-2 * time.Minute))}},
+						},
+					},
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "app", Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+					},
+				},
+			},
+			PodTableRow{Name: "test3", Ready: "2/2", Status: "Running", Restarts: "1 (2m ago)", Age: "10m"},
+		},
+	}
+
+	for i, test := range tests {
+		row := NewPodTableRow(&test.pod, now)
+		if !reflect.DeepEqual(test.expect, row) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, row))
+		}
+	}
+}
+
+func TestFormatPodTable(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pods := []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-7d4b9c8f5-x2k4p", CreationTimestamp: metav1.NewTime(now.Add(-2 * 24 * time.Hour))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             "Running",
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-0", CreationTimestamp: metav1.NewTime(now.Add(-30 * time.Second))},
+			Spec:       apiv1.PodSpec{InitContainers: make([]apiv1.Container, 1), Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase: "Pending",
+				InitContainerStatuses: []apiv1.ContainerStatus{
+					{State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+				},
+			},
+		},
+	}
+
+	expect := "" +
+		"NAME                  READY   STATUS     RESTARTS   AGE\n" +
+		"web-7d4b9c8f5-x2k4p   1/1     Running    0          2d\n" +
+		"db-0                  0/1     Init:0/1   0          30s\n"
+	table := FormatPodTable(pods, now)
+	if !reflect.DeepEqual(expect, table) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, table))
+	}
+}
+
+func TestWritePodRows(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	frontend := []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             "Running",
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		},
+	}
+	backend := []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "postgres-primary-0", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: "Pending"},
+		},
+	}
+
+	var buf bytes.Buffer
+	tw := newTabWriter(&buf)
+	if err := WritePodRows(tw, frontend, now, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := WritePodRows(tw, backend, now, false); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("WritePodRows flushed the caller's tabwriter")
+	}
+	tw.Flush()
+
+	expect := "" +
+		"NAME                 READY   STATUS    RESTARTS   AGE\n" +
+		"web                  1/1     Running   0          60m\n" +
+		"postgres-primary-0   0/1     Pending   0          60s\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+}
+
+func TestNewPodTableRowWide(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pod    apiv1.Pod
+		expect PodTableRowWide
+	}{
+		{
+			// Test scheduled pod with an IP and readiness gates
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test1", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Spec: apiv1.PodSpec{
+					NodeName:       "node-1",
+					Containers:     make([]apiv1.Container, 1),
+					ReadinessGates: []apiv1.PodReadinessGate{{ConditionType: "example.com/lb"}, {ConditionType: "example.com/dns"}},
+				},
+				Status: apiv1.PodStatus{
+					Phase:             "Running",
+					PodIP:             "10.0.0.12",
+					ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+					Conditions: []apiv1.PodCondition{
+						{Type: "example.com/lb", Status: apiv1.ConditionTrue},
+						{Type: "example.com/dns", Status: apiv1.ConditionFalse},
+					},
+				},
+			},
+			PodTableRowWide{
+				PodTableRow:    PodTableRow{Name: "test1", Ready: "1/1", Status: "Running", Restarts: "0", Age: "60m"},
+				IP:             "10.0.0.12",
+				Node:           "node-1",
+				NominatedNode:  "<none>",
+				ReadinessGates: "1/2",
+				RestartReason:  "<none>",
+			},
+		},
+		{
+			// Test pending pod without any placement
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test2", CreationTimestamp: metav1.NewTime(now.Add(-5 * time.Second))},
+				Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+				Status:     apiv1.PodStatus{Phase: "Pending"},
+			},
+			PodTableRowWide{
+				PodTableRow:    PodTableRow{Name: "test2", Ready: "0/1", Status: "Pending", Restarts: "0", Age: "5s"},
+				IP:             "<none>",
+				Node:           "<none>",
+				NominatedNode:  "<none>",
+				ReadinessGates: "<none>",
+				RestartReason:  "<none>",
+			},
+		},
+		{
+			// Test container restarted after being OOM killed
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test3", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							Ready:                true,
+							RestartCount:         1,
+							State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137, FinishedAt: metav1.NewTime(now.Add(-30 * time.Minute))}},
+						},
+					},
+				},
+			},
+			PodTableRowWide{
+				PodTableRow:    PodTableRow{Name: "test3", Ready: "1/1", Status: "Running", Restarts: "1 (30m ago)", Age: "60m"},
+				IP:             "<none>",
+				Node:           "node-1",
+				NominatedNode:  "<none>",
+				ReadinessGates: "<none>",
+				RestartReason:  "OOMKilled",
+			},
+		},
+		{
+			// Test container restarted after exiting with an error
+			apiv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test4", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)},
+				Status: apiv1.PodStatus{
+					Phase: "Running",
+					ContainerStatuses: []apiv1.ContainerStatus{
+						{
+							RestartCount:         3,
+							State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+							LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+						},
+					},
+				},
+			},
+			PodTableRowWide{
+				PodTableRow:    PodTableRow{Name: "test4", Ready: "0/1", Status: "CrashLoopBackOff", Restarts: "3 (60s ago)", Age: "60m"},
+				IP:             "<none>",
+				Node:           "node-1",
+				NominatedNode:  "<none>",
+				ReadinessGates: "<none>",
+				RestartReason:  "Error",
+			},
+		},
+	}
+
+	for i, test := range tests {
+		row := NewPodTableRowWide(&test.pod, now)
+		if !reflect.DeepEqual(test.expect, row) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, row))
+		}
+	}
+}
+
+func TestFormatterEmptyPlaceholder(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-5 * time.Second))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status:     apiv1.PodStatus{Phase: "Pending"},
+	}
+	f := Formatter{EmptyPlaceholder: "-"}
+
+	expect := PodTableRowWide{
+		PodTableRow:    PodTableRow{Name: "web", Ready: "0/1", Status: "Pending", Restarts: "0", Age: "5s"},
+		IP:             "-",
+		Node:           "-",
+		NominatedNode:  "-",
+		ReadinessGates: "-",
+		RestartReason:  "-",
+	}
+	if row := f.NewPodTableRowWide(&pod, now); !reflect.DeepEqual(expect, row) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, row))
+	}
+	if node, err := f.ExtractField(&pod, "node", now); err != nil || node != "-" {
+		t.Errorf("got (%q, %v), expected (%q, nil)", node, err, "-")
+	}
+}
+
+func TestFormatterNameTruncation(t *testing.T) {
+	name := "web-frontend-deployment-7d4b9c8f5b-x7p2q"
+
+	tests := []struct {
+		f      Formatter
+		expect string
+	}{
+		{Formatter{MaxNameWidth: 20, NameTruncateMode: TruncateMiddle}, "web-fronte…f5b-x7p2q"},
+		{Formatter{MaxNameWidth: 20, NameTruncateMode: TruncateTrailing}, "web-frontend-deploy…"},
+		{Formatter{MaxNameWidth: 40, NameTruncateMode: TruncateMiddle}, name},
+		{Formatter{NameTruncateMode: TruncateMiddle}, name},
+	}
+
+	for i, test := range tests {
+		truncated := test.f.truncateName(name)
+		if !reflect.DeepEqual(test.expect, truncated) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, truncated))
+		}
+		if n := len([]rune(truncated)); test.f.MaxNameWidth > 0 && n > test.f.MaxNameWidth {
+			t.Errorf("%d truncated to %d runes, expected at most %d", i, n, test.f.MaxNameWidth)
+		}
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pods := []apiv1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status:     apiv1.PodStatus{Phase: "Pending"},
+	}}
+	expect := "" +
+		"NAME                   READY   STATUS    RESTARTS   AGE\n" +
+		"web-fronte…f5b-x7p2q   0/1     Pending   0          60m\n"
+	table := Formatter{MaxNameWidth: 20, NameTruncateMode: TruncateMiddle}.FormatPodTable(pods, now)
+	if !reflect.DeepEqual(expect, table) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, table))
+	}
+}
+
+func TestFormatterReadySeparator(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 2)},
+		Status: apiv1.PodStatus{
+			Phase:             "Running",
+			ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+		},
+	}
+
+	tests := []struct {
+		separator string
+		expect    string
+	}{
+		{"", "1/2"},
+		{"/", "1/2"},
+		{"|", "1|2"},
+		{" of ", "1 of 2"},
+	}
+
+	for i, test := range tests {
+		f := Formatter{ReadySeparator: test.separator}
+		if ready := f.NewPodTableRow(&pod, now).Ready; ready != test.expect {
+			t.Errorf("%d mismatch: got %q, expected %q", i, ready, test.expect)
+		}
+		if ready, err := f.ExtractField(&pod, "ready", now); err != nil || ready != test.expect {
+			t.Errorf("%d got (%q, %v), expected (%q, nil)", i, ready, err, test.expect)
+		}
+	}
+
+	var buf bytes.Buffer
+	f := DefaultFormatter
+	f.ReadySeparator = " of "
+	if err := f.printPodTableWide(&buf, &apiv1.PodList{Items: []apiv1.Pod{pod}}, now); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(buf.String(), "\n"); len(lines) < 2 || !strings.HasPrefix(lines[1], "web    1 of 2   Running") {
+		t.Errorf("expected the separator in the wide table:\n%s", buf.String())
+	}
+}
+
+func TestFormatterRestartingWithin(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase: "Running",
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{
+					Ready:                true,
+					RestartCount:         1,
+					State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+					LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error", FinishedAt: metav1.NewTime(now.Add(-30 * time.Second))}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		within time.Duration
+		expect string
+	}{
+		{0, "Running"},
+		{10 * time.Second, "Running"},
+		{time.Minute, "Running (restarting)"},
+	}
+
+	for i, test := range tests {
+		f := Formatter{RestartingWithin: test.within}
+		if status := f.NewPodTableRow(&pod, now).Status; status != test.expect {
+			t.Errorf("%d mismatch: got %q, expected %q", i, status, test.expect)
+		}
+	}
+
+	f := DefaultFormatter
+	f.RestartingWithin = time.Minute
+	table := f.FormatPodTable([]apiv1.Pod{pod}, now)
+	if lines := strings.Split(table, "\n"); len(lines) < 2 || !strings.HasPrefix(lines[1], "web    1/1     Running (restarting)") {
+		t.Errorf("expected the suffix in the STATUS column:\n%s", table)
+	}
+}
+
+func TestExtractField(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-5 * time.Minute))},
+		Spec:       apiv1.PodSpec{NodeName: "node-1", Containers: make([]apiv1.Container, 1)},
+		Status: apiv1.PodStatus{
+			Phase: "Running",
+			PodIP: "10.0.0.7",
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{
+					Ready:                true,
+					RestartCount:         2,
+					State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+					LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-time.Minute))}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		field     string
+		expect    string
+		expectErr bool
+	}{
+		{"name", "web", false},
+		{"status", "Running", false},
+		{"ready", "1/1", false},
+		{"restarts", "2 (60s ago)", false},
+		{"age", "5m", false},
+		{"ip", "10.0.0.7", false},
+		{"node", "node-1", false},
+		{"namespace", "", true},
+	}
+
+	for i, test := range tests {
+		value, err := ExtractField(&pod, test.field, now)
+		if (err != nil) != test.expectErr {
+			t.Fatalf("%d unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(test.expect, value) {
+			t.Errorf("%d mismatch: %s", i, cmp.Diff(test.expect, value))
+		}
+	}
+}
+
+func TestFormatPodTableWithRecentRestarts(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pods := []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-3 * 24 * time.Hour))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase: "Running",
+				ContainerStatuses: []apiv1.ContainerStatus{
+					{
+						Ready:                true,
+						RestartCount:         5,
+						State:                apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+						LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-2 * time.Minute))}},
+					},
+				},
+			},
+		},
+	}
+
+	expect := "" +
+		"NAME   READY   STATUS    RESTARTS     RESTARTS(1h)   AGE\n" +
+		"web    1/1     Running   5 (2m ago)   1              3d\n"
+	table := FormatPodTableWithRecentRestarts(pods, now, time.Hour)
+	if !reflect.DeepEqual(expect, table) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, table))
+	}
+}
+
+func TestDebugIDs(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Generation: 3, ResourceVersion: "48213", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+		Status:     apiv1.PodStatus{Phase: "Pending"},
+	}
+
+	generation, resourceVersion := DebugIDs(&pod)
+	if generation != 3 || resourceVersion != "48213" {
+		t.Errorf("mismatch: got (%d, %q), expected (3, \"48213\")", generation, resourceVersion)
+	}
+
+	expect := "" +
+		"NAME   READY   STATUS    RESTARTS   GENERATION   RESOURCE-VERSION   AGE\n" +
+		"web    0/1     Pending   0          3            48213              60s\n"
+	table := FormatPodTableDebug([]apiv1.Pod{pod}, now)
+	if !reflect.DeepEqual(expect, table) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, table))
+	}
+}
+
+func TestPrintPodTableAllNamespaces(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pods := apiv1.PodList{Items: []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns-5d78c9869d-abcde", Namespace: "kube-system", CreationTimestamp: metav1.NewTime(now.Add(-3 * 24 * time.Hour))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status: apiv1.PodStatus{
+				Phase:             "Running",
+				ContainerStatuses: []apiv1.ContainerStatus{{Ready: true, State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Spec:       apiv1.PodSpec{Containers: make([]apiv1.Container, 1)},
+			Status:     apiv1.PodStatus{Phase: "Pending"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := DefaultFormatter.printPodTableAllNamespaces(&buf, &pods, now); err != nil {
+		t.Fatal(err)
+	}
+	expect := "" +
+		"NAMESPACE     NAME                       READY   STATUS    RESTARTS   AGE\n" +
+		"kube-system   coredns-5d78c9869d-abcde   1/1     Running   0          3d\n" +
+		"default       web                        0/1     Pending   0          60m\n"
+	if !reflect.DeepEqual(expect, buf.String()) {
+		t.Errorf("mismatch: %s", cmp.Diff(expect, buf.String()))
+	}
+
+	buf.Reset()
+	if err := PrintPodTableAllNamespaces(&buf, &pods, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "NAMESPACE") || !strings.HasSuffix(buf.String(), "\n\n1 Pending, 1 Running\n") {
+		t.Errorf("missing summary:\n%s", buf.String())
+	}
+}